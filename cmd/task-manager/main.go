@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
@@ -30,52 +31,47 @@ func run() int {
 		return 1
 	}
 
-	auth := cfg.DBUser
-	if cfg.DBPassword != "" {
-		auth = fmt.Sprintf("%s:%s", cfg.DBUser, cfg.DBPassword)
-	}
-	dsn := fmt.Sprintf("postgres://%s@%s:%s/%s?sslmode=disable", auth, cfg.DBHost, cfg.DBPort, cfg.DBName)
-	db, err := sql.Open("pgx", dsn)
-	if err != nil {
-		log.Printf("Не удалось подключиться к базе данных: %v", err)
-		return 1
-	}
-	defer db.Close()
-
-	for i := 1; i <= 5; i++ {
-		err = db.Ping()
-		if err == nil {
-			log.Println("Успешное подключение к базе данных")
-			break
+	var service *task.Service
+	switch cfg.Storage {
+	case config.StorageMemory:
+		log.Println("Используется хранилище в памяти, данные не сохранятся после остановки")
+		service = newMemoryService()
+	case config.StoragePostgres:
+		db, err := openPostgres(cfg)
+		if err != nil {
+			log.Printf("Не удалось подключиться к базе данных: %v", err)
+			return 1
 		}
-		log.Printf("Попытка %d: база данных недоступна, ожидание %d сек...", i, 2*i)
-		time.Sleep(time.Duration(2*i) * time.Second)
-	}
-
-	if err != nil {
-		log.Printf("Не удалось установить соединение с базой данных после 5 попыток: %v", err)
-		return 1
-	}
-	log.Println("Подключение к PostgreSQL выполнено успешно")
-
-	/*
-		initCtx, initCancel := context.WithTimeout(context.Background(), 2*time.Second)
-		err = createTable(initCtx, db)
-		initCancel()
+		defer db.Close()
+		service, err = newPostgresService(cfg, db)
 		if err != nil {
-			log.Printf("Ошибка создания таблицы в базе данных: %v", err)
+			log.Printf("Ошибка инициализации хранилища вложений: %v", err)
 			return 1
 		}
-	*/
+	case config.StorageSQLite:
+		db, err := openSQLite(cfg.SQLitePath)
+		if err != nil {
+			log.Printf("Не удалось открыть базу SQLite: %v", err)
+			return 1
+		}
+		defer db.Close()
+		service = newSQLiteService(db)
+	default:
+		log.Printf("Неизвестный тип хранилища: %q", cfg.Storage)
+		return 1
+	}
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	go service.RunTrashPurge(purgeCtx, cfg.TrashRetention, cfg.TrashPurgeInterval)
 
-	repo := task.NewPostgresRepository(db)
-	groups := task.NewPostgresGroupRepository(db)
-	service := task.NewService(repo, groups)
-	handler := api.NewHandler(service)
+	handler := api.NewHandler(service, cfg.RequireIfMatch)
 	handlerGroup := api.NewGroupHandler(service)
+	handlerTag := api.NewTagHandler(service)
+	handlerUser := api.NewUserHandler(service)
 
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
+	r.Use(api.Actor)
 
 	r.Route("/tasks", func(r chi.Router) {
 		r.Post("/", handler.CreateTask)
@@ -83,14 +79,63 @@ func run() int {
 		r.Get("/{id}", handler.GetTask)
 		r.Put("/{id}", handler.UpdateTask)
 		r.Delete("/{id}", handler.DeleteTask)
+		r.Get("/{id}/subtasks", handler.GetSubtasks)
+		r.Get("/{id}/occurrences", handler.GetOccurrences)
+		r.Get("/{id}/dependencies", handler.GetBlockers)
+		r.Post("/{id}/dependencies", handler.AddDependency)
+		r.Delete("/{id}/dependencies/{blocker_id}", handler.RemoveDependency)
+		r.Put("/{id}/tags/{tag_id}", handler.AssignTag)
+		r.Delete("/{id}/tags/{tag_id}", handler.UnassignTag)
+		r.Get("/{id}/comments", handler.ListComments)
+		r.Post("/{id}/comments", handler.AddComment)
+		r.Put("/{id}/comments/{cid}", handler.UpdateComment)
+		r.Delete("/{id}/comments/{cid}", handler.DeleteComment)
+		r.Get("/{id}/attachments", handler.ListAttachments)
+		r.Post("/{id}/attachments", handler.UploadAttachment)
+		r.Get("/{id}/attachments/{aid}", handler.DownloadAttachment)
+		r.Delete("/{id}/attachments/{aid}", handler.DeleteAttachment)
+		r.Get("/{id}/history", handler.GetTaskHistory)
+		r.Post("/{id}/restore", handler.RestoreTask)
+		r.Post("/{id}/move", handler.MoveTask)
 	})
 
+	r.Get("/audit", handler.GetAuditLog)
+	r.Get("/trash", handler.GetTrash)
+	r.Get("/stats", handler.GetStats)
+
 	r.Route("/groups", func(r chi.Router) {
 		r.Post("/", handlerGroup.CreateGroup)
 		r.Get("/", handlerGroup.ListGroups)
+		r.Get("/tree", handlerGroup.GetGroupTree)
+		r.Get("/by-slug/{slug}", handlerGroup.GetGroupBySlug)
 		r.Get("/{id}", handlerGroup.GetGroup)
 		r.Put("/{id}", handlerGroup.UpdateGroup)
 		r.Delete("/{id}", handlerGroup.DeleteGroup)
+		r.Post("/{id}/merge", handlerGroup.MergeGroup)
+		r.Get("/{id}/stats", handlerGroup.GetGroupStats)
+		r.Get("/{id}/workflow", handlerGroup.GetWorkflow)
+		r.Put("/{id}/workflow", handlerGroup.SetWorkflow)
+		r.Delete("/{id}/workflow", handlerGroup.ResetWorkflow)
+		r.Post("/{id}/restore", handlerGroup.RestoreGroup)
+		r.Get("/{id}/children", handlerGroup.GetGroupChildren)
+		r.Post("/{id}/archive", handlerGroup.ArchiveGroup)
+		r.Post("/{id}/unarchive", handlerGroup.UnarchiveGroup)
+	})
+
+	r.Route("/tags", func(r chi.Router) {
+		r.Post("/", handlerTag.CreateTag)
+		r.Get("/", handlerTag.ListTags)
+		r.Get("/{id}", handlerTag.GetTag)
+		r.Put("/{id}", handlerTag.UpdateTag)
+		r.Delete("/{id}", handlerTag.DeleteTag)
+	})
+
+	r.Route("/users", func(r chi.Router) {
+		r.Post("/", handlerUser.CreateUser)
+		r.Get("/", handlerUser.ListUsers)
+		r.Get("/{id}", handlerUser.GetUser)
+		r.Put("/{id}", handlerUser.UpdateUser)
+		r.Delete("/{id}", handlerUser.DeleteUser)
 	})
 
 	log.Printf("Запуск сервера на порту :%s...", cfg.ServerPort)
@@ -131,6 +176,113 @@ func run() int {
 	return 0
 }
 
+func openPostgres(cfg config.Config) (*sql.DB, error) {
+	auth := cfg.DBUser
+	if cfg.DBPassword != "" {
+		auth = fmt.Sprintf("%s:%s", cfg.DBUser, cfg.DBPassword)
+	}
+	dsn := fmt.Sprintf("postgres://%s@%s:%s/%s?sslmode=disable", auth, cfg.DBHost, cfg.DBPort, cfg.DBName)
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i <= 5; i++ {
+		err = db.Ping()
+		if err == nil {
+			log.Println("Успешное подключение к базе данных")
+			break
+		}
+		log.Printf("Попытка %d: база данных недоступна, ожидание %d сек...", i, 2*i)
+		time.Sleep(time.Duration(2*i) * time.Second)
+	}
+
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("no connection after 5 attempts: %w", err)
+	}
+	log.Println("Подключение к PostgreSQL выполнено успешно")
+
+	/*
+		initCtx, initCancel := context.WithTimeout(context.Background(), 2*time.Second)
+		err = createTable(initCtx, db)
+		initCancel()
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("create table: %w", err)
+		}
+	*/
+	return db, nil
+}
+
+func newPostgresService(cfg config.Config, db *sql.DB) (*task.Service, error) {
+	repo := task.NewPostgresRepository(db)
+	groups := task.NewPostgresGroupRepository(db)
+	dependencies := task.NewPostgresDependencyRepository(db)
+	tags := task.NewPostgresTagRepository(db)
+	users := task.NewPostgresUserRepository(db)
+	comments := task.NewPostgresCommentRepository(db)
+	attachments := task.NewPostgresAttachmentRepository(db)
+	events := task.NewPostgresEventRepository(db)
+	attachmentStore, err := task.NewLocalAttachmentStore(cfg.AttachmentsDir)
+	if err != nil {
+		return nil, err
+	}
+	return task.NewService(
+		repo,
+		groups,
+		task.WithStore(task.NewPostgresStore(db)),
+		task.WithDependencies(dependencies),
+		task.WithTags(tags),
+		task.WithUsers(users),
+		task.WithComments(comments),
+		task.WithEvents(events),
+		task.WithStats(repo),
+		task.WithAttachments(attachments, attachmentStore, task.AttachmentLimits{
+			MaxSize:      cfg.AttachmentMaxSize,
+			AllowedTypes: cfg.AttachmentMIMETypes,
+		}),
+	), nil
+}
+
+// newMemoryService keeps only tasks and groups; the other features answer
+// with 501 Not Implemented.
+func newMemoryService() *task.Service {
+	db := task.NewMemoryDB()
+	repo := task.NewMemoryRepository(db)
+	return task.NewService(
+		repo,
+		task.NewMemoryGroupRepository(db),
+		task.WithStore(task.NewMemoryStore(db)),
+		task.WithStats(repo),
+	)
+}
+
+func openSQLite(path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	db, err := task.OpenSQLite(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Используется база SQLite %s", path)
+	return db, nil
+}
+
+// newSQLiteService, like newMemoryService, keeps only tasks and groups.
+func newSQLiteService(db *sql.DB) *task.Service {
+	repo := task.NewSQLiteRepository(db)
+	return task.NewService(
+		repo,
+		task.NewSQLiteGroupRepository(db),
+		task.WithStore(task.NewSQLiteStore(db)),
+		task.WithStats(repo),
+	)
+}
+
 /*
 func createTable(ctx context.Context, db *sql.DB) error {
 	query := `CREATE TABLE IF NOT EXISTS tasks (
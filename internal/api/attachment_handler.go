@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/just4fun-xd/task-manager/internal/task"
+)
+
+// multipartOverhead leaves room for multipart headers and boundaries on top
+// of the attachment size limit.
+const multipartOverhead = 1 << 20
+
+func (h *Handler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	if maxSize := h.service.MaxAttachmentSize(); maxSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+	}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "multipart/form-data body expected", http.StatusBadRequest)
+		return
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			http.Error(w, "file part is required", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			writeAttachmentError(w, err)
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+		a, err := h.service.UploadAttachment(r.Context(), id, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			writeAttachmentError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(a)
+		return
+	}
+}
+
+func (h *Handler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	attachments, err := h.service.ListAttachments(r.Context(), id)
+	if err != nil {
+		writeAttachmentError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(attachments)
+}
+
+func (h *Handler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	attachmentId, ok := GetIdParam(w, r, "aid")
+	if !ok {
+		return
+	}
+	a, rc, err := h.service.OpenAttachment(r.Context(), id, attachmentId)
+	if err != nil {
+		writeAttachmentError(w, err)
+		return
+	}
+	defer rc.Close()
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, rc)
+}
+
+func (h *Handler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	attachmentId, ok := GetIdParam(w, r, "aid")
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteAttachment(r.Context(), id, attachmentId); err != nil {
+		writeAttachmentError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAttachmentError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, task.ErrAttachmentTooLarge), errors.As(err, &maxBytesErr):
+		http.Error(w, task.ErrAttachmentTooLarge.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, task.ErrAttachmentType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, task.ErrEmptyAttachmentName), errors.Is(err, task.ErrAttachmentNameLong), errors.Is(err, task.ErrEmptyAttachmentBlob), errors.Is(err, task.ErrDoneEdit):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, task.ErrTaskNotFound), errors.Is(err, task.ErrAttachmentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, task.ErrAttachmentsDisabled):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/just4fun-xd/task-manager/internal/task"
+)
+
+type CommentRequest struct {
+	Body string `json:"body"`
+}
+
+func (h *Handler) AddComment(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	var req CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	var authorId *int
+	if userId, ok := CurrentUserID(r); ok {
+		authorId = &userId
+	}
+	c, err := h.service.AddComment(r.Context(), id, authorId, req.Body)
+	if err != nil {
+		writeCommentError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(c)
+}
+
+func (h *Handler) ListComments(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	comments, err := h.service.ListComments(r.Context(), id)
+	if err != nil {
+		writeCommentError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(comments)
+}
+
+func (h *Handler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	commentId, ok := GetIdParam(w, r, "cid")
+	if !ok {
+		return
+	}
+	var req CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	c, err := h.service.UpdateComment(r.Context(), id, commentId, req.Body)
+	if err != nil {
+		writeCommentError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(c)
+}
+
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	commentId, ok := GetIdParam(w, r, "cid")
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteComment(r.Context(), id, commentId); err != nil {
+		writeCommentError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeCommentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, task.ErrEmptyComment), errors.Is(err, task.ErrDoneEdit), errors.Is(err, task.ErrUserNotFound):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, task.ErrTaskNotFound), errors.Is(err, task.ErrCommentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, task.ErrCommentsDisabled):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/just4fun-xd/task-manager/internal/task"
+)
+
+type DependencyRequest struct {
+	BlockerID int `json:"blocker_id"`
+}
+
+func (h *Handler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	var req DependencyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	err := h.service.AddDependency(r.Context(), id, req.BlockerID)
+	if err != nil {
+		writeDependencyError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(task.Dependency{BlockerID: req.BlockerID, BlockedID: id})
+}
+
+func (h *Handler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	blockerId, ok := GetIdParam(w, r, "blocker_id")
+	if !ok {
+		return
+	}
+	if err := h.service.RemoveDependency(r.Context(), id, blockerId); err != nil {
+		writeDependencyError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetBlockers(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	t, err := h.service.GetBlockers(r.Context(), id)
+	if err != nil {
+		writeDependencyError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(t)
+}
+
+func writeDependencyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, task.ErrSelfDependency):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, task.ErrTaskNotFound), errors.Is(err, task.ErrDependencyNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, task.ErrDependencyCycle), errors.Is(err, task.ErrDependencyExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, task.ErrDependenciesDisabled):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
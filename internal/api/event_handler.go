@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/just4fun-xd/task-manager/internal/task"
+)
+
+func (h *Handler) GetTaskHistory(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	events, err := h.service.GetTaskHistory(r.Context(), id)
+	if err != nil {
+		writeEventError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(events)
+}
+
+func (h *Handler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	since, ok := GetTimeParam(w, r, "since")
+	if !ok {
+		return
+	}
+	if since == nil {
+		t := time.Now().Add(-24 * time.Hour)
+		since = &t
+	}
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			http.Error(w, "invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	events, err := h.service.GetAuditLog(r.Context(), *since, limit)
+	if err != nil {
+		writeEventError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(events)
+}
+
+func writeEventError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, task.ErrTaskNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, task.ErrAuditDisabled):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/just4fun-xd/task-manager/internal/task"
 )
 
@@ -19,7 +21,27 @@ func NewGroupHandler(service *task.Service) *GroupHandler {
 }
 
 type GroupRequest struct {
-	Name string `json:"name"`
+	Name        string `json:"name"`
+	ParentID    *int   `json:"parent_id"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+	Icon        string `json:"icon"`
+	OwnerID     *int   `json:"owner_id"`
+	Archived    bool   `json:"archived"`
+	WIPLimit    *int   `json:"wip_limit"`
+}
+
+func (req GroupRequest) toInput() task.GroupInput {
+	return task.GroupInput{
+		Name:        req.Name,
+		ParentID:    req.ParentID,
+		Description: req.Description,
+		Color:       req.Color,
+		Icon:        req.Icon,
+		OwnerID:     req.OwnerID,
+		Archived:    req.Archived,
+		WIPLimit:    req.WIPLimit,
+	}
 }
 
 func (h *GroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
@@ -28,12 +50,28 @@ func (h *GroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid requst body", http.StatusBadRequest)
 		return
 	}
-	g, err := h.service.CreateGroup(r.Context(), req.Name)
+	g, err := h.service.CreateGroup(r.Context(), req.toInput())
 	if err != nil {
 		if errors.Is(err, task.ErrEmptyGroupName) || errors.Is(err, task.ErrNotUniqGroup) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		if errors.Is(err, task.ErrParentGroupNotFound) || errors.Is(err, task.ErrUserNotFound) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrUsersDisabled) {
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		}
+		if errors.Is(err, task.ErrInvalidGroupColor) || errors.Is(err, task.ErrInvalidGroupIcon) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrInvalidWIPLimit) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -57,12 +95,32 @@ func (h *GroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid request body", http.StatusBadRequest)
 		return
 	}
-	g, err := h.service.UpdateGroup(r.Context(), id, req.Name)
+	g, err := h.service.UpdateGroup(r.Context(), id, req.toInput())
 	if err != nil {
 		if errors.Is(err, task.ErrEmptyGroupName) || errors.Is(err, task.ErrNotUniqGroup) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		if errors.Is(err, task.ErrParentGroupNotFound) || errors.Is(err, task.ErrInvalidGroupParent) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrInvalidGroupColor) || errors.Is(err, task.ErrInvalidGroupIcon) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrInvalidWIPLimit) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrUserNotFound) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrUsersDisabled) {
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		}
 		if errors.Is(err, task.ErrGroupNotFound) {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
@@ -75,8 +133,25 @@ func (h *GroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(g)
 }
 
+func includeArchived(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	value := r.URL.Query().Get("archived")
+	if value == "" {
+		return false, true
+	}
+	archived, err := strconv.ParseBool(value)
+	if err != nil {
+		http.Error(w, "invalid archived parameter", http.StatusBadRequest)
+		return false, false
+	}
+	return archived, true
+}
+
 func (h *GroupHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
-	groups, err := h.service.ListGroup(r.Context())
+	archived, ok := includeArchived(w, r)
+	if !ok {
+		return
+	}
+	groups, err := h.service.ListGroup(r.Context(), archived)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -89,6 +164,40 @@ func (h *GroupHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(groups)
 }
 
+func (h *GroupHandler) GetGroupTree(w http.ResponseWriter, r *http.Request) {
+	archived, ok := includeArchived(w, r)
+	if !ok {
+		return
+	}
+	groups, err := h.service.GetGroupTree(r.Context(), archived)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(groups)
+}
+
+func (h *GroupHandler) GetGroupChildren(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	groups, err := h.service.GetGroupChildren(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, task.ErrGroupNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(groups)
+}
+
 func (h *GroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
 	id, ok := GetId(w, r)
 	if !ok {
@@ -108,17 +217,151 @@ func (h *GroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(g)
 }
 
+func (h *GroupHandler) GetGroupBySlug(w http.ResponseWriter, r *http.Request) {
+	g, err := h.service.GetGroupBySlug(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		if errors.Is(err, task.ErrGroupNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(g)
+}
+
+type DeleteGroupResponse struct {
+	Strategy      task.GroupDeleteStrategy `json:"strategy"`
+	AffectedTasks int                      `json:"affected_tasks"`
+}
+
 func (h *GroupHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 	id, ok := GetId(w, r)
 	if !ok {
 		return
 	}
-	err := h.service.DeleteGroup(r.Context(), id)
+	q := r.URL.Query()
+	strategy := task.GroupDeleteStrategy(q.Get("strategy"))
+	var targetId *int
+	if targetStr := q.Get("target"); targetStr != "" {
+		target, err := strconv.Atoi(targetStr)
+		if err != nil {
+			http.Error(w, "invalid target parameter", http.StatusBadRequest)
+			return
+		}
+		targetId = &target
+	}
+	affected, err := h.service.DeleteGroup(r.Context(), id, strategy, targetId)
+	if err != nil {
+		if errors.Is(err, task.ErrInvalidDeleteStrategy) || errors.Is(err, task.ErrTargetGroupNotFound) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrGroupArchived) || errors.Is(err, task.ErrWIPLimitExceeded) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, task.ErrGroupHasTasks) || errors.Is(err, task.ErrGroupHasChildren) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, task.ErrTaskHasSubtasks) || errors.Is(err, task.ErrNotUniqGroup) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, task.ErrInvalidStatus) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, task.ErrGroupNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(DeleteGroupResponse{Strategy: strategy, AffectedTasks: affected})
+}
+
+type MergeGroupRequest struct {
+	Source int  `json:"source"`
+	DryRun bool `json:"dry_run"`
+}
+
+func (h *GroupHandler) MergeGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	var req MergeGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	merge, err := h.service.MergeGroups(r.Context(), id, req.Source, req.DryRun)
 	if err != nil {
-		if errors.Is(err, task.ErrGroupHasTasks) {
+		if errors.Is(err, task.ErrInvalidMerge) || errors.Is(err, task.ErrSourceGroupNotFound) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrGroupArchived) || errors.Is(err, task.ErrWIPLimitExceeded) {
 			http.Error(w, err.Error(), http.StatusConflict)
 			return
 		}
+		if errors.Is(err, task.ErrInvalidStatus) || errors.Is(err, task.ErrNotUniqGroup) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, task.ErrGroupNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(merge)
+}
+
+func (h *GroupHandler) ArchiveGroup(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, true)
+}
+
+func (h *GroupHandler) UnarchiveGroup(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, false)
+}
+
+func (h *GroupHandler) setArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	g, err := h.service.SetGroupArchived(r.Context(), id, archived)
+	if err != nil {
+		if errors.Is(err, task.ErrGroupNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(g)
+}
+
+func (h *GroupHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	wf, err := h.service.GetWorkflow(r.Context(), id)
+	if err != nil {
 		if errors.Is(err, task.ErrGroupNotFound) {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
@@ -126,5 +369,47 @@ func (h *GroupHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(wf)
+}
+
+func (h *GroupHandler) SetWorkflow(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	var req *task.Workflow
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	h.writeWorkflow(w, r, id, req)
+}
+
+func (h *GroupHandler) ResetWorkflow(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	h.writeWorkflow(w, r, id, nil)
+}
+
+func (h *GroupHandler) writeWorkflow(w http.ResponseWriter, r *http.Request, id int, wf *task.Workflow) {
+	wf, err := h.service.SetWorkflow(r.Context(), id, wf)
+	if err != nil {
+		if errors.Is(err, task.ErrInvalidWorkflow) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrGroupNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(wf)
 }
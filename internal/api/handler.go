@@ -5,24 +5,35 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/just4fun-xd/task-manager/internal/task"
 )
 
 type Handler struct {
-	service *task.Service
+	service        *task.Service
+	requireIfMatch bool
 }
 
-func NewHandler(s *task.Service) *Handler {
+// NewHandler builds the task handler; with requireIfMatch set updates and
+// deletes are refused unless they carry an If-Match header.
+func NewHandler(s *task.Service, requireIfMatch bool) *Handler {
 	return &Handler{
-		service: s,
+		service:        s,
+		requireIfMatch: requireIfMatch,
 	}
 }
 
 type CreateTaskRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	GroupID     *int   `json:"group_id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	GroupID     *int              `json:"group_id"`
+	ParentID    *int              `json:"parent_id"`
+	AssigneeID  *int              `json:"assignee_id"`
+	StartAt     *time.Time        `json:"start_at"`
+	DueAt       *time.Time        `json:"due_at"`
+	Recurrence  *string           `json:"recurrence"`
+	Priority    task.TaskPriority `json:"priority"`
 }
 
 type UpdateTaskRequest struct {
@@ -30,6 +41,20 @@ type UpdateTaskRequest struct {
 	Status task.TaskStatus `json:"status"`
 }
 
+func (req CreateTaskRequest) toInput() task.CreateTaskInput {
+	return task.CreateTaskInput{
+		Name:        req.Name,
+		Description: req.Description,
+		GroupID:     req.GroupID,
+		ParentID:    req.ParentID,
+		AssigneeID:  req.AssigneeID,
+		StartAt:     req.StartAt,
+		DueAt:       req.DueAt,
+		Recurrence:  req.Recurrence,
+		Priority:    req.Priority,
+	}
+}
+
 func (h *Handler) CreateTask(w http.ResponseWriter, r *http.Request) {
 	var req CreateTaskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -37,16 +62,45 @@ func (h *Handler) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	t, err := h.service.CreateTask(r.Context(), req.Name, req.Description, req.GroupID)
+	t, err := h.service.CreateTask(r.Context(), req.toInput())
 	if err != nil {
 		if errors.Is(err, task.ErrEmptyTaskName) || errors.Is(err, task.ErrGroupNotFound) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		if errors.Is(err, task.ErrInvalidPriority) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrGroupArchived) || errors.Is(err, task.ErrWIPLimitExceeded) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, task.ErrDueBeforeCreated) || errors.Is(err, task.ErrDueBeforeStart) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrInvalidRecurrence) || errors.Is(err, task.ErrRecurrenceNeedsDue) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrParentNotFound) || errors.Is(err, task.ErrUserNotFound) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrUsersDisabled) {
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		}
+		if errors.Is(err, task.ErrParentClosed) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	SetETag(w, t.Version)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusCreated)
 
@@ -61,7 +115,8 @@ func (h *Handler) GetTask(w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		return
 	}
-	t, err := h.service.GetTask(r.Context(), id)
+	includeSubtasks := r.URL.Query().Get("include") == "subtasks"
+	t, err := h.service.GetTaskDetails(r.Context(), id, includeSubtasks)
 	if err != nil {
 		if errors.Is(err, task.ErrTaskNotFound) {
 			http.Error(w, err.Error(), http.StatusNotFound)
@@ -70,24 +125,104 @@ func (h *Handler) GetTask(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	SetETag(w, t.Version)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	json.NewEncoder(w).Encode(t)
 }
 
 func (h *Handler) GetAllTasks(w http.ResponseWriter, r *http.Request) {
-	groupIdStr := r.URL.Query().Get("group_id")
-	var groupId *int
+	q := r.URL.Query()
+	var filter task.TaskFilter
+	groupIdStr := q.Get("group_id")
 	if groupIdStr != "" {
 		groupIdTemp, err := strconv.Atoi(groupIdStr)
 		if err != nil {
 			http.Error(w, "invalid group_id parameter", http.StatusBadRequest)
 			return
 		}
-		groupId = &groupIdTemp
+		filter.GroupID = &groupIdTemp
+	}
+	if slug := q.Get("group"); slug != "" {
+		if filter.GroupID != nil {
+			http.Error(w, "group and group_id parameters are mutually exclusive", http.StatusBadRequest)
+			return
+		}
+		filter.GroupSlug = slug
+	}
+	if statusStr := q.Get("status"); statusStr != "" {
+		status := task.TaskStatus(statusStr)
+		if !status.IsValid() {
+			http.Error(w, "invalid status parameter", http.StatusBadRequest)
+			return
+		}
+		filter.Status = &status
+	}
+	if recursiveStr := q.Get("recursive"); recursiveStr != "" {
+		recursive, err := strconv.ParseBool(recursiveStr)
+		if err != nil {
+			http.Error(w, "invalid recursive parameter", http.StatusBadRequest)
+			return
+		}
+		filter.Recursive = recursive
+	}
+	switch assignee := q.Get("assignee"); assignee {
+	case "":
+	case "me":
+		userId, ok := CurrentUserID(r)
+		if !ok {
+			http.Error(w, "X-User-ID header is required for assignee=me", http.StatusUnauthorized)
+			return
+		}
+		filter.AssigneeID = &userId
+	default:
+		userId, err := strconv.Atoi(assignee)
+		if err != nil {
+			http.Error(w, "invalid assignee parameter", http.StatusBadRequest)
+			return
+		}
+		filter.AssigneeID = &userId
+	}
+	var ok bool
+	if filter.DueBefore, ok = GetTimeParam(w, r, "due_before"); !ok {
+		return
 	}
+	if filter.DueAfter, ok = GetTimeParam(w, r, "due_after"); !ok {
+		return
+	}
+	if overdueStr := q.Get("overdue"); overdueStr != "" {
+		overdue, err := strconv.ParseBool(overdueStr)
+		if err != nil {
+			http.Error(w, "invalid overdue parameter", http.StatusBadRequest)
+			return
+		}
+		filter.Overdue = overdue
+	}
+	filter.Tags = q["tag"]
+	switch q.Get("tag_mode") {
+	case "", "any":
+	case "all":
+		filter.AllTags = true
+	default:
+		http.Error(w, "invalid tag_mode parameter", http.StatusBadRequest)
+		return
+	}
+	sort, err := task.ParseSort(q.Get("sort"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.Sort = sort
 
-	t, err := h.service.GetAllTasks(r.Context(), groupId)
+	t, err := h.service.GetAllTasks(r.Context(), filter)
 	if err != nil {
+		if errors.Is(err, task.ErrGroupNotFound) || errors.Is(err, task.ErrUserNotFound) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrUsersDisabled) {
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -101,11 +236,23 @@ func (h *Handler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		return
 	}
-	if err := h.service.DeleteTask(r.Context(), id); err != nil {
+	version, ok := IfMatchVersion(w, r, h.requireIfMatch)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteTask(r.Context(), id, version); err != nil {
 		if errors.Is(err, task.ErrTaskNotFound) {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, task.ErrConflict) {
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+			return
+		}
+		if errors.Is(err, task.ErrTaskHasSubtasks) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -127,12 +274,60 @@ func (h *Handler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid task status", http.StatusBadRequest)
 		return
 	}
-	t, err := h.service.UpdateTask(r.Context(), id, req.Name, req.Description, req.Status, req.GroupID)
+	version, ok := IfMatchVersion(w, r, h.requireIfMatch)
+	if !ok {
+		return
+	}
+	t, err := h.service.UpdateTask(r.Context(), id, task.UpdateTaskInput{
+		CreateTaskInput: req.toInput(),
+		Status:          req.Status,
+		Version:         version,
+	})
 	if err != nil {
-		if errors.Is(err, task.ErrEmptyTaskName) || errors.Is(err, task.ErrNewTaskStatus) {
+		if errors.Is(err, task.ErrConflict) {
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+			return
+		}
+		if errors.Is(err, task.ErrEmptyTaskName) || errors.Is(err, task.ErrInvalidStatus) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		if errors.Is(err, task.ErrInvalidPriority) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrGroupArchived) || errors.Is(err, task.ErrWIPLimitExceeded) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, task.ErrTransitionNotAllowed) || errors.Is(err, task.ErrGuardFailed) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrDueBeforeCreated) || errors.Is(err, task.ErrDueBeforeStart) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrInvalidRecurrence) || errors.Is(err, task.ErrRecurrenceNeedsDue) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrParentNotFound) || errors.Is(err, task.ErrInvalidParent) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrUserNotFound) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, task.ErrUsersDisabled) {
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		}
+		if errors.Is(err, task.ErrOpenSubtasks) || errors.Is(err, task.ErrTaskBlocked) || errors.Is(err, task.ErrParentClosed) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		if errors.Is(err, task.ErrDoneEdit) || errors.Is(err, task.ErrGroupNotFound) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
@@ -144,7 +339,66 @@ func (h *Handler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	SetETag(w, t.Version)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(t)
 }
+
+func (h *Handler) GetSubtasks(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	t, err := h.service.GetSubtasks(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, task.ErrTaskNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(t)
+}
+
+func (h *Handler) GetOccurrences(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	from, ok := GetTimeParam(w, r, "from")
+	if !ok {
+		return
+	}
+	to, ok := GetTimeParam(w, r, "to")
+	if !ok {
+		return
+	}
+	if from == nil {
+		now := time.Now()
+		from = &now
+	}
+	if to == nil {
+		defaultTo := from.AddDate(0, 3, 0)
+		to = &defaultTo
+	}
+	occurrences, err := h.service.GetOccurrences(r.Context(), id, *from, *to)
+	if err != nil {
+		if errors.Is(err, task.ErrTaskNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, task.ErrTaskNotRecurring) || errors.Is(err, task.ErrInvalidOccurrenceRange) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(occurrences)
+}
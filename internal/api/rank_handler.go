@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/just4fun-xd/task-manager/internal/task"
+)
+
+type MoveTaskRequest struct {
+	After  *int `json:"after"`
+	Before *int `json:"before"`
+}
+
+func (h *Handler) MoveTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	var req MoveTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	t, err := h.service.MoveTask(r.Context(), id, req.After, req.Before)
+	if err != nil {
+		switch {
+		case errors.Is(err, task.ErrInvalidMove), errors.Is(err, task.ErrNeighborNotFound):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, task.ErrTaskNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	SetETag(w, t.Version)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(t)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/just4fun-xd/task-manager/internal/task"
+)
+
+const defaultStatsRange = 30 * 24 * time.Hour
+
+func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := statsRange(w, r)
+	if !ok {
+		return
+	}
+	stats, err := h.service.GetStats(r.Context(), from, to)
+	if err != nil {
+		writeStatsError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stats)
+}
+
+func (h *GroupHandler) GetGroupStats(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	from, to, ok := statsRange(w, r)
+	if !ok {
+		return
+	}
+	recursive := false
+	if value := r.URL.Query().Get("recursive"); value != "" {
+		var err error
+		if recursive, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, "invalid recursive parameter", http.StatusBadRequest)
+			return
+		}
+	}
+	stats, err := h.service.GetGroupStats(r.Context(), id, recursive, from, to)
+	if err != nil {
+		writeStatsError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stats)
+}
+
+// statsRange reads the from/to parameters; by default the last 30 days.
+func statsRange(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	from, ok := GetTimeParam(w, r, "from")
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	to, ok := GetTimeParam(w, r, "to")
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	if to == nil {
+		now := time.Now()
+		to = &now
+	}
+	if from == nil {
+		defaultFrom := to.Add(-defaultStatsRange)
+		from = &defaultFrom
+	}
+	return *from, *to, true
+}
+
+func writeStatsError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, task.ErrGroupNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, task.ErrInvalidStatsRange):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, task.ErrStatsDisabled):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
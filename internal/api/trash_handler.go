@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/just4fun-xd/task-manager/internal/task"
+)
+
+func (h *Handler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	trash, err := h.service.GetTrash(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(trash)
+}
+
+func (h *Handler) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	t, err := h.service.RestoreTask(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, task.ErrTaskNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, task.ErrGroupNotFound), errors.Is(err, task.ErrParentNotFound):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, task.ErrWIPLimitExceeded), errors.Is(err, task.ErrParentClosed):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	SetETag(w, t.Version)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(t)
+}
+
+func (h *GroupHandler) RestoreGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetId(w, r)
+	if !ok {
+		return
+	}
+	g, err := h.service.RestoreGroup(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, task.ErrGroupNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, task.ErrNotUniqGroup), errors.Is(err, task.ErrParentGroupNotFound):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(g)
+}
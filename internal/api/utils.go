@@ -3,16 +3,85 @@ package api
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/just4fun-xd/task-manager/internal/task"
 )
 
 func GetId(w http.ResponseWriter, r *http.Request) (int, bool) {
-	idStr := chi.URLParam(r, "id")
+	return GetIdParam(w, r, "id")
+}
+
+func GetIdParam(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	idStr := chi.URLParam(r, name)
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "invalid id", http.StatusBadRequest)
+		http.Error(w, "invalid "+name, http.StatusBadRequest)
 		return 0, false
 	}
 	return id, true
 }
+
+func GetTimeParam(w http.ResponseWriter, r *http.Request, name string) (*time.Time, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		http.Error(w, "invalid "+name+" parameter", http.StatusBadRequest)
+		return nil, false
+	}
+	return &t, true
+}
+
+// SetETag exposes the task version as a strong entity tag.
+func SetETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// IfMatchVersion reads the version a write is conditioned on from the
+// If-Match header; nil means any version will do. The header may only be
+// omitted when it is not required.
+func IfMatchVersion(w http.ResponseWriter, r *http.Request, required bool) (*int, bool) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" {
+		if required {
+			http.Error(w, "If-Match header is required", http.StatusPreconditionRequired)
+			return nil, false
+		}
+		return nil, true
+	}
+	if value == "*" {
+		return nil, true
+	}
+	unquoted, err := strconv.Unquote(value)
+	version, convErr := strconv.Atoi(unquoted)
+	if err != nil || convErr != nil {
+		http.Error(w, "If-Match does not match the task version", http.StatusPreconditionFailed)
+		return nil, false
+	}
+	return &version, true
+}
+
+// CurrentUserID returns the caller id passed in the X-User-ID header.
+func CurrentUserID(r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.Header.Get("X-User-ID"))
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// Actor stores the caller id in the request context so that the service can
+// attribute audit events to it.
+func Actor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := CurrentUserID(r); ok {
+			r = r.WithContext(task.WithActor(r.Context(), id))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
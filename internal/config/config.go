@@ -3,18 +3,36 @@ package config
 import (
 	"errors"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
 )
 
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+	StorageSQLite   = "sqlite"
+)
+
 type Config struct {
 	ServerPort string `env:"SERVER_PORT" env-default:"8080"`
+	Storage    string `env:"STORAGE" env-default:"postgres"`
 	DBHost     string `env:"DB_HOST" env-default:"localhost"`
 	DBPort     string `env:"DB_PORT" env-default:"5432"`
 	DBUser     string `env:"DB_USER" env-default:"user"`
 	DBPassword string `env:"DB_PASSWORD" env-default:""`
 	DBName     string `env:"DB_NAME" env-default:""`
+	SQLitePath string `env:"SQLITE_PATH" env-default:"./data/tasks.db"`
+
+	RequireIfMatch bool `env:"REQUIRE_IF_MATCH" env-default:"false"`
+
+	AttachmentsDir      string   `env:"ATTACHMENTS_DIR" env-default:"./data/attachments"`
+	AttachmentMaxSize   int64    `env:"ATTACHMENT_MAX_SIZE" env-default:"10485760"`
+	AttachmentMIMETypes []string `env:"ATTACHMENT_MIME_TYPES" env-separator:"," env-default:"image/png,image/jpeg,image/gif,image/webp,text/plain,application/pdf,application/zip"`
+
+	TrashRetention     time.Duration `env:"TRASH_RETENTION" env-default:"720h"`
+	TrashPurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL" env-default:"1h"`
 }
 
 func LoadConfig() (Config, error) {
@@ -0,0 +1,36 @@
+package task
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+type Attachment struct {
+	ID          int       `json:"id"`
+	TaskID      int       `json:"task_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-"`
+	Created     time.Time `json:"created"`
+}
+
+type AttachmentRepository interface {
+	Add(ctx context.Context, attachment *Attachment) error
+	GetByTask(ctx context.Context, taskId int) ([]Attachment, error)
+	GetById(ctx context.Context, id int) (*Attachment, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// AttachmentStore keeps attachment contents; metadata lives in AttachmentRepository.
+type AttachmentStore interface {
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type AttachmentLimits struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
@@ -0,0 +1,188 @@
+package task
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrAttachmentsDisabled = errors.New("attachments are not configured")
+	ErrAttachmentNotFound  = errors.New("attachment not found")
+	ErrAttachmentTooLarge  = errors.New("attachment is too large")
+	ErrAttachmentType      = errors.New("attachment type is not allowed")
+	ErrEmptyAttachmentName = errors.New("attachment file name cannot be empty")
+	ErrAttachmentNameLong  = errors.New("attachment file name is too long")
+	ErrEmptyAttachmentBlob = errors.New("attachment cannot be empty")
+)
+
+// maxAttachmentNameLen matches the attachments.filename column.
+const maxAttachmentNameLen = 256
+
+func (s *Service) UploadAttachment(ctx context.Context, taskId int, filename string, r io.Reader) (*Attachment, error) {
+	if s.attachments == nil {
+		return nil, ErrAttachmentsDisabled
+	}
+	filename = filepath.Base(strings.TrimSpace(filename))
+	if filename == "" || filename == "." || filename == string(filepath.Separator) {
+		return nil, ErrEmptyAttachmentName
+	}
+	if utf8.RuneCountInString(filename) > maxAttachmentNameLen {
+		return nil, ErrAttachmentNameLong
+	}
+	if _, err := s.editableTask(ctx, taskId); err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if len(head) == 0 {
+		return nil, ErrEmptyAttachmentBlob
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if len(s.attachmentLimits.AllowedTypes) > 0 && !slices.Contains(s.attachmentLimits.AllowedTypes, contentType) {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentType, contentType)
+	}
+
+	key, err := newStorageKey()
+	if err != nil {
+		return nil, err
+	}
+	var body io.Reader = br
+	if s.attachmentLimits.MaxSize > 0 {
+		body = io.LimitReader(br, s.attachmentLimits.MaxSize+1)
+	}
+	size, err := s.attachmentStore.Put(ctx, key, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	if s.attachmentLimits.MaxSize > 0 && size > s.attachmentLimits.MaxSize {
+		s.removeBlob(ctx, key)
+		return nil, ErrAttachmentTooLarge
+	}
+
+	attachment := &Attachment{
+		TaskID:      taskId,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+		Created:     time.Now(),
+	}
+	if err := s.attachments.Add(ctx, attachment); err != nil {
+		s.removeBlob(ctx, key)
+		return nil, fmt.Errorf("failed to add attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+func (s *Service) MaxAttachmentSize() int64 {
+	return s.attachmentLimits.MaxSize
+}
+
+func (s *Service) ListAttachments(ctx context.Context, taskId int) ([]Attachment, error) {
+	if s.attachments == nil {
+		return nil, ErrAttachmentsDisabled
+	}
+	if _, err := s.GetTask(ctx, taskId); err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachments.GetByTask(ctx, taskId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// OpenAttachment returns the attachment metadata and its contents; the caller
+// must close the reader.
+func (s *Service) OpenAttachment(ctx context.Context, taskId, id int) (*Attachment, io.ReadCloser, error) {
+	attachment, err := s.taskAttachment(ctx, taskId, id, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, err := s.attachmentStore.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+	return attachment, rc, nil
+}
+
+func (s *Service) DeleteAttachment(ctx context.Context, taskId, id int) error {
+	attachment, err := s.taskAttachment(ctx, taskId, id, true)
+	if err != nil {
+		return err
+	}
+	if err := s.attachments.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	s.removeBlob(ctx, attachment.StorageKey)
+	return nil
+}
+
+func (s *Service) taskAttachment(ctx context.Context, taskId, id int, editable bool) (*Attachment, error) {
+	if s.attachments == nil {
+		return nil, ErrAttachmentsDisabled
+	}
+	lookup := s.GetTask
+	if editable {
+		lookup = s.editableTask
+	}
+	if _, err := lookup(ctx, taskId); err != nil {
+		return nil, err
+	}
+	attachment, err := s.attachments.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	if attachment.TaskID != taskId {
+		return nil, ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// taskBlobs lists storage keys of the task attachments so they can be removed
+// once the task row (and the metadata with it) is gone.
+func (s *Service) taskBlobs(ctx context.Context, taskId int) ([]string, error) {
+	if s.attachments == nil {
+		return nil, nil
+	}
+	attachments, err := s.attachments.GetByTask(ctx, taskId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	keys := make([]string, len(attachments))
+	for i, a := range attachments {
+		keys[i] = a.StorageKey
+	}
+	return keys, nil
+}
+
+func (s *Service) removeBlob(ctx context.Context, key string) {
+	if err := s.attachmentStore.Delete(ctx, key); err != nil {
+		log.Printf("Не удалось удалить вложение %s: %v", key, err)
+	}
+}
+
+func newStorageKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate attachment key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
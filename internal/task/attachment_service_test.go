@@ -0,0 +1,142 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type MockAttachmentRepository struct {
+	Added       *Attachment
+	Attachments []Attachment
+}
+
+func (m *MockAttachmentRepository) Add(ctx context.Context, a *Attachment) error {
+	a.ID = 1
+	m.Added = a
+	return nil
+}
+func (m *MockAttachmentRepository) GetByTask(ctx context.Context, taskId int) ([]Attachment, error) {
+	return m.Attachments, nil
+}
+func (m *MockAttachmentRepository) GetById(ctx context.Context, id int) (*Attachment, error) {
+	return nil, ErrAttachmentNotFound
+}
+func (m *MockAttachmentRepository) Delete(ctx context.Context, id int) error { return nil }
+
+func TestUploadAttachment(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+	tests := []struct {
+		name        string
+		filename    string
+		data        []byte
+		expectedErr error
+		wantType    string
+	}{
+		{name: "Текстовый лог", data: []byte("panic: runtime error\n"), wantType: "text/plain"},
+		{name: "Скриншот", data: png, wantType: "image/png"},
+		{name: "Ошибка: слишком большой", data: []byte(strings.Repeat("a", 101)), expectedErr: ErrAttachmentTooLarge},
+		{name: "Ошибка: запрещённый тип", data: []byte("%PDF-1.4\n"), expectedErr: ErrAttachmentType},
+		{name: "Ошибка: пустой файл", data: nil, expectedErr: ErrEmptyAttachmentBlob},
+		{name: "Ошибка: длинное имя", filename: strings.Repeat("я", 253) + ".txt", data: []byte("лог"), expectedErr: ErrAttachmentNameLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			store, err := NewLocalAttachmentStore(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			mockAttachments := &MockAttachmentRepository{}
+			mockRepo := &MockRepository{TaskToReturn: &Task{ID: 1, Status: StatusNew}}
+			service := NewService(mockRepo, nil, WithAttachments(mockAttachments, store, AttachmentLimits{
+				MaxSize:      100,
+				AllowedTypes: []string{"text/plain", "image/png"},
+			}))
+			filename := tt.filename
+			if filename == "" {
+				filename = "../log.txt"
+			}
+			a, err := service.UploadAttachment(context.Background(), 1, filename, bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			files, _ := os.ReadDir(dir)
+			if err != nil {
+				if len(files) != 0 {
+					t.Errorf("после ошибки в хранилище остались файлы: %d", len(files))
+				}
+				return
+			}
+			if a.ContentType != tt.wantType || a.Size != int64(len(tt.data)) || a.Filename != "log.txt" {
+				t.Errorf("неверные метаданные: %+v", a)
+			}
+			got, err := os.ReadFile(filepath.Join(dir, a.StorageKey))
+			if err != nil || !bytes.Equal(got, tt.data) {
+				t.Errorf("содержимое не совпадает: %v", err)
+			}
+		})
+	}
+}
+
+func TestDeleteTask_KeepsAttachments(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalAttachmentStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Put(context.Background(), "abc", strings.NewReader("data")); err != nil {
+		t.Fatal(err)
+	}
+	mockAttachments := &MockAttachmentRepository{Attachments: []Attachment{{ID: 1, TaskID: 1, StorageKey: "abc"}}}
+	mockRepo := &MockRepository{TaskToReturn: &Task{ID: 1, Status: StatusDone}}
+	service := NewService(mockRepo, nil, WithAttachments(mockAttachments, store, AttachmentLimits{}))
+	if err := service.DeleteTask(context.Background(), 1, nil); err != nil {
+		t.Fatalf("не ожидалось ошибки, получена: %v", err)
+	}
+	if _, err := store.Open(context.Background(), "abc"); err != nil {
+		t.Errorf("вложение задачи в корзине не должно удаляться: %v", err)
+	}
+}
+
+func TestPurgeTrash_RemovesAttachments(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalAttachmentStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Put(context.Background(), "abc", strings.NewReader("data")); err != nil {
+		t.Fatal(err)
+	}
+	mockAttachments := &MockAttachmentRepository{Attachments: []Attachment{{ID: 1, TaskID: 1, StorageKey: "abc"}}}
+	mockRepo := &MockRepository{TrashToReturn: []Task{{ID: 1, Status: StatusDone}}}
+	service := NewService(mockRepo, &MockGroupRepository{}, WithAttachments(mockAttachments, store, AttachmentLimits{}))
+	purged, err := service.PurgeTrash(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("не ожидалось ошибки, получена: %v", err)
+	}
+	if purged != 1 || len(mockRepo.PurgedIDs) != 1 {
+		t.Errorf("ожидалось удаление одной задачи, удалено %d", purged)
+	}
+	if _, err := store.Open(context.Background(), "abc"); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("вложение не удалено: %v", err)
+	}
+}
+
+func TestLocalAttachmentStore_InvalidKey(t *testing.T) {
+	store, err := NewLocalAttachmentStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"", "../etc/passwd", ".hidden", "a/b"} {
+		if _, err := store.Put(context.Background(), key, io.LimitReader(nil, 0)); err == nil {
+			t.Errorf("ключ %q должен быть отклонён", key)
+		}
+	}
+}
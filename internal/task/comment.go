@@ -0,0 +1,23 @@
+package task
+
+import (
+	"context"
+	"time"
+)
+
+type Comment struct {
+	ID       int        `json:"id"`
+	TaskID   int        `json:"task_id"`
+	AuthorID *int       `json:"author_id"`
+	Body     string     `json:"body"`
+	Created  time.Time  `json:"created"`
+	Updated  *time.Time `json:"updated"`
+}
+
+type CommentRepository interface {
+	Add(ctx context.Context, comment *Comment) error
+	GetByTask(ctx context.Context, taskId int) ([]Comment, error)
+	GetById(ctx context.Context, id int) (*Comment, error)
+	Update(ctx context.Context, comment *Comment) error
+	Delete(ctx context.Context, id int) error
+}
@@ -0,0 +1,119 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCommentsDisabled = errors.New("comments are not configured")
+	ErrEmptyComment     = errors.New("comment body cannot be empty")
+	ErrCommentNotFound  = errors.New("comment not found")
+)
+
+func (s *Service) AddComment(ctx context.Context, taskId int, authorId *int, body string) (*Comment, error) {
+	if s.comments == nil {
+		return nil, ErrCommentsDisabled
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, ErrEmptyComment
+	}
+	if _, err := s.editableTask(ctx, taskId); err != nil {
+		return nil, err
+	}
+	if err := s.checkUser(ctx, authorId); err != nil {
+		return nil, err
+	}
+	comment := &Comment{
+		TaskID:   taskId,
+		AuthorID: authorId,
+		Body:     body,
+		Created:  time.Now(),
+	}
+	if err := s.comments.Add(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to add comment: %w", err)
+	}
+	return comment, nil
+}
+
+func (s *Service) ListComments(ctx context.Context, taskId int) ([]Comment, error) {
+	if s.comments == nil {
+		return nil, ErrCommentsDisabled
+	}
+	if _, err := s.GetTask(ctx, taskId); err != nil {
+		return nil, err
+	}
+	comments, err := s.comments.GetByTask(ctx, taskId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	return comments, nil
+}
+
+func (s *Service) UpdateComment(ctx context.Context, taskId, id int, body string) (*Comment, error) {
+	if s.comments == nil {
+		return nil, ErrCommentsDisabled
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, ErrEmptyComment
+	}
+	comment, err := s.taskComment(ctx, taskId, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	comment.Body = body
+	comment.Updated = &now
+	if err := s.comments.Update(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+	return comment, nil
+}
+
+func (s *Service) DeleteComment(ctx context.Context, taskId, id int) error {
+	if s.comments == nil {
+		return ErrCommentsDisabled
+	}
+	if _, err := s.taskComment(ctx, taskId, id); err != nil {
+		return err
+	}
+	if err := s.comments.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) taskComment(ctx context.Context, taskId, id int) (*Comment, error) {
+	if _, err := s.editableTask(ctx, taskId); err != nil {
+		return nil, err
+	}
+	comment, err := s.comments.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	if comment.TaskID != taskId {
+		return nil, ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// editableTask returns the task unless it is in a terminal state of its workflow.
+func (s *Service) editableTask(ctx context.Context, id int) (*Task, error) {
+	task, err := s.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	workflow, err := s.workflowFor(ctx, task.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if workflow.IsTerminal(task.Status) {
+		return nil, ErrDoneEdit
+	}
+	return task, nil
+}
@@ -0,0 +1,72 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type MockCommentRepository struct {
+	AddCalled       bool
+	UpdateCalled    bool
+	CommentToReturn *Comment
+}
+
+func (m *MockCommentRepository) Add(ctx context.Context, comment *Comment) error {
+	m.AddCalled = true
+	return nil
+}
+func (m *MockCommentRepository) GetByTask(ctx context.Context, taskId int) ([]Comment, error) {
+	return nil, nil
+}
+func (m *MockCommentRepository) GetById(ctx context.Context, id int) (*Comment, error) {
+	if m.CommentToReturn == nil {
+		return nil, ErrCommentNotFound
+	}
+	return m.CommentToReturn, nil
+}
+func (m *MockCommentRepository) Update(ctx context.Context, comment *Comment) error {
+	m.UpdateCalled = true
+	return nil
+}
+func (m *MockCommentRepository) Delete(ctx context.Context, id int) error { return nil }
+
+func TestAddComment(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      TaskStatus
+		body        string
+		expectedErr error
+	}{
+		{name: "Успешный комментарий", status: StatusInProgress, body: "Готово на 80%"},
+		{name: "Ошибка: пустой текст", status: StatusInProgress, body: "  ", expectedErr: ErrEmptyComment},
+		{name: "Ошибка: задача завершена", status: StatusDone, body: "Ещё одно", expectedErr: ErrDoneEdit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockComments := &MockCommentRepository{}
+			mockRepo := &MockRepository{TaskToReturn: &Task{ID: 1, Status: tt.status}}
+			service := NewService(mockRepo, nil, WithComments(mockComments))
+			_, err := service.AddComment(context.Background(), 1, nil, tt.body)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if mockComments.AddCalled != (tt.expectedErr == nil) {
+				t.Errorf("AddCalled = %v, а ожидалось %v", mockComments.AddCalled, tt.expectedErr == nil)
+			}
+		})
+	}
+}
+
+func TestUpdateComment_OtherTask(t *testing.T) {
+	mockComments := &MockCommentRepository{CommentToReturn: &Comment{ID: 3, TaskID: 2, Body: "Текст"}}
+	mockRepo := &MockRepository{TaskToReturn: &Task{ID: 1, Status: StatusNew}}
+	service := NewService(mockRepo, nil, WithComments(mockComments))
+	_, err := service.UpdateComment(context.Background(), 1, 3, "Новый текст")
+	if !errors.Is(err, ErrCommentNotFound) {
+		t.Fatalf("ожидалась ошибка %v, получена %v", ErrCommentNotFound, err)
+	}
+	if mockComments.UpdateCalled {
+		t.Error("комментарий чужой задачи не должен обновляться")
+	}
+}
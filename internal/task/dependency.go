@@ -0,0 +1,15 @@
+package task
+
+import "context"
+
+type Dependency struct {
+	BlockerID int `json:"blocker_id"`
+	BlockedID int `json:"blocked_id"`
+}
+
+type DependencyRepository interface {
+	Add(ctx context.Context, dep Dependency) error
+	Delete(ctx context.Context, dep Dependency) error
+	GetBlockers(ctx context.Context, taskId int) ([]Task, error)
+	GetBlockedIDs(ctx context.Context, taskId int) ([]int, error)
+}
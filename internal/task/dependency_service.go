@@ -0,0 +1,118 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrDependenciesDisabled = errors.New("task dependencies are not configured")
+	ErrSelfDependency       = errors.New("task cannot block itself")
+	ErrDependencyCycle      = errors.New("dependency would create a cycle")
+	ErrDependencyExists     = errors.New("dependency already exists")
+	ErrDependencyNotFound   = errors.New("dependency not found")
+	ErrTaskBlocked          = errors.New("task is blocked by unfinished tasks")
+)
+
+func (s *Service) AddDependency(ctx context.Context, blockedId, blockerId int) error {
+	if s.dependencies == nil {
+		return ErrDependenciesDisabled
+	}
+	if blockedId == blockerId {
+		return ErrSelfDependency
+	}
+	// The cycle check and the insert share a transaction, so two links added
+	// concurrently cannot close a cycle between them.
+	return s.atomically(ctx, func(tx *Service) error {
+		if _, err := tx.GetTask(ctx, blockedId); err != nil {
+			return err
+		}
+		if _, err := tx.GetTask(ctx, blockerId); err != nil {
+			return err
+		}
+		cycle, err := tx.reachable(ctx, blockedId, blockerId)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return ErrDependencyCycle
+		}
+		err = tx.dependencies.Add(ctx, Dependency{BlockerID: blockerId, BlockedID: blockedId})
+		if err != nil {
+			return fmt.Errorf("failed to add dependency: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *Service) RemoveDependency(ctx context.Context, blockedId, blockerId int) error {
+	if s.dependencies == nil {
+		return ErrDependenciesDisabled
+	}
+	err := s.dependencies.Delete(ctx, Dependency{BlockerID: blockerId, BlockedID: blockedId})
+	if err != nil {
+		return fmt.Errorf("failed to delete dependency: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) GetBlockers(ctx context.Context, id int) ([]Task, error) {
+	if s.dependencies == nil {
+		return nil, ErrDependenciesDisabled
+	}
+	if _, err := s.GetTask(ctx, id); err != nil {
+		return nil, err
+	}
+	blockers, err := s.dependencies.GetBlockers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blockers: %w", err)
+	}
+	return blockers, nil
+}
+
+// reachable reports whether target can be reached from start by following
+// "blocks" links, i.e. whether start already (transitively) blocks target.
+func (s *Service) reachable(ctx context.Context, start, target int) (bool, error) {
+	visited := map[int]bool{start: true}
+	queue := []int{start}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		blocked, err := s.dependencies.GetBlockedIDs(ctx, id)
+		if err != nil {
+			return false, fmt.Errorf("failed to get blocked tasks: %w", err)
+		}
+		for _, next := range blocked {
+			if next == target {
+				return true, nil
+			}
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false, nil
+}
+
+func (s *Service) checkBlockers(ctx context.Context, id int) error {
+	if s.dependencies == nil {
+		return nil
+	}
+	blockers, err := s.dependencies.GetBlockers(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get blockers: %w", err)
+	}
+	cache := map[int]*Workflow{}
+	for _, blocker := range blockers {
+		workflow, err := s.cachedWorkflow(ctx, cache, blocker.GroupID)
+		if err != nil {
+			return err
+		}
+		if !workflow.IsTerminal(blocker.Status) {
+			return fmt.Errorf("%w: task %d is %s", ErrTaskBlocked, blocker.ID, blocker.Status)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type MockDependencyRepository struct {
+	Blocks   map[int][]int
+	Blockers []Task
+	Added    *Dependency
+}
+
+func (m *MockDependencyRepository) Add(ctx context.Context, dep Dependency) error {
+	m.Added = &dep
+	return nil
+}
+func (m *MockDependencyRepository) Delete(ctx context.Context, dep Dependency) error { return nil }
+func (m *MockDependencyRepository) GetBlockers(ctx context.Context, taskId int) ([]Task, error) {
+	return m.Blockers, nil
+}
+func (m *MockDependencyRepository) GetBlockedIDs(ctx context.Context, taskId int) ([]int, error) {
+	return m.Blocks[taskId], nil
+}
+
+func TestAddDependency(t *testing.T) {
+	tests := []struct {
+		name        string
+		blocks      map[int][]int
+		blocked     int
+		blocker     int
+		expectedErr error
+	}{
+		{name: "Успешная связь", blocks: map[int][]int{1: {2}}, blocked: 3, blocker: 2},
+		{name: "Ошибка: сама себя", blocked: 1, blocker: 1, expectedErr: ErrSelfDependency},
+		{name: "Ошибка: прямой цикл", blocks: map[int][]int{1: {2}}, blocked: 1, blocker: 2, expectedErr: ErrDependencyCycle},
+		{name: "Ошибка: транзитивный цикл", blocks: map[int][]int{1: {2}, 2: {3}}, blocked: 1, blocker: 3, expectedErr: ErrDependencyCycle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{TaskToReturn: &Task{Status: StatusNew}}
+			mockDeps := &MockDependencyRepository{Blocks: tt.blocks}
+			service := NewService(mockRepo, nil, WithDependencies(mockDeps))
+			err := service.AddDependency(context.Background(), tt.blocked, tt.blocker)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if (mockDeps.Added != nil) != (tt.expectedErr == nil) {
+				t.Errorf("связь сохранена: %v, а ожидалось %v", mockDeps.Added != nil, tt.expectedErr == nil)
+			}
+		})
+	}
+}
+
+func TestUpdateTask_Blocked(t *testing.T) {
+	groupID := 1
+	shortcut := &Workflow{
+		Initial:     StatusNew,
+		States:      []TaskStatus{StatusNew, StatusDone},
+		Terminal:    []TaskStatus{StatusDone},
+		Transitions: []Transition{{From: StatusNew, To: StatusDone}},
+	}
+	tests := []struct {
+		name        string
+		blockers    []Task
+		groupID     *int
+		status      TaskStatus
+		expectedErr error
+	}{
+		{name: "Блокеры завершены", blockers: []Task{{ID: 2, Status: StatusDone}}, status: StatusInProgress},
+		{name: "Ошибка: блокер в работе", blockers: []Task{{ID: 2, Status: StatusInProgress}}, status: StatusInProgress, expectedErr: ErrTaskBlocked},
+		{name: "Ошибка: закрытие в обход работы", blockers: []Task{{ID: 2, Status: StatusNew}}, groupID: &groupID, status: StatusDone, expectedErr: ErrTaskBlocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{TaskToReturn: &Task{ID: 1, Status: StatusNew, GroupID: tt.groupID}}
+			mockDeps := &MockDependencyRepository{Blockers: tt.blockers}
+			groups := &MockGroupRepository{GroupToReturn: &Group{ID: groupID}, WorkflowToReturn: shortcut}
+			service := NewService(mockRepo, groups, WithDependencies(mockDeps))
+			_, err := service.UpdateTask(context.Background(), 1, UpdateTaskInput{
+				CreateTaskInput: CreateTaskInput{Name: "Задача", GroupID: tt.groupID},
+				Status:          tt.status,
+			})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if mockRepo.UpdateCalled != (tt.expectedErr == nil) {
+				t.Errorf("UpdateCalled = %v, а ожидалось %v", mockRepo.UpdateCalled, tt.expectedErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+type EventAction string
+
+const (
+	ActionCreate  EventAction = "create"
+	ActionUpdate  EventAction = "update"
+	ActionDelete  EventAction = "delete"
+	ActionRestore EventAction = "restore"
+	ActionPurge   EventAction = "purge"
+)
+
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type TaskEvent struct {
+	ID      int64                  `json:"id"`
+	TaskID  int                    `json:"task_id"`
+	ActorID *int                   `json:"actor_id"`
+	Action  EventAction            `json:"action"`
+	Changes map[string]FieldChange `json:"changes"`
+	Created time.Time              `json:"created"`
+}
+
+type EventRepository interface {
+	Add(ctx context.Context, event *TaskEvent) error
+	GetByTask(ctx context.Context, taskId int) ([]TaskEvent, error)
+	GetSince(ctx context.Context, since time.Time, limit int) ([]TaskEvent, error)
+}
+
+type actorKey struct{}
+
+// WithActor marks ctx with the id of the user performing the operation.
+func WithActor(ctx context.Context, userId int) context.Context {
+	return context.WithValue(ctx, actorKey{}, userId)
+}
+
+func ActorFromContext(ctx context.Context) *int {
+	if id, ok := ctx.Value(actorKey{}).(int); ok {
+		return &id
+	}
+	return nil
+}
+
+// auditedFields are the JSON names of the stored task fields; derived ones
+// such as group_name or progress are left out of the diff.
+var auditedFields = []string{
+	"name", "description", "status", "priority", "group_id", "parent_id", "assignee_id",
+	"start_at", "due_at", "recurrence",
+}
+
+func diffTasks(before, after *Task) map[string]FieldChange {
+	b := taskFields(before)
+	a := taskFields(after)
+	changes := map[string]FieldChange{}
+	for _, field := range auditedFields {
+		if !reflect.DeepEqual(b[field], a[field]) {
+			changes[field] = FieldChange{Before: b[field], After: a[field]}
+		}
+	}
+	return changes
+}
+
+func taskFields(t *Task) map[string]any {
+	fields := map[string]any{}
+	if t == nil {
+		return fields
+	}
+	copied := *t
+	copied.StartAt = utcTime(t.StartAt)
+	copied.DueAt = utcTime(t.DueAt)
+	data, err := json.Marshal(copied)
+	if err != nil {
+		return fields
+	}
+	json.Unmarshal(data, &fields)
+	return fields
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
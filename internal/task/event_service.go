@@ -0,0 +1,69 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const maxAuditEvents = 1000
+
+var ErrAuditDisabled = errors.New("audit trail is not configured")
+
+func (s *Service) GetTaskHistory(ctx context.Context, id int) ([]TaskEvent, error) {
+	if s.events == nil {
+		return nil, ErrAuditDisabled
+	}
+	events, err := s.events.GetByTask(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task history: %w", err)
+	}
+	if len(events) == 0 {
+		if _, err := s.GetTask(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+func (s *Service) GetAuditLog(ctx context.Context, since time.Time, limit int) ([]TaskEvent, error) {
+	if s.events == nil {
+		return nil, ErrAuditDisabled
+	}
+	if limit <= 0 || limit > maxAuditEvents {
+		limit = maxAuditEvents
+	}
+	events, err := s.events.GetSince(ctx, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %w", err)
+	}
+	return events, nil
+}
+
+// record stores an audit entry for a task change; before is nil for created
+// tasks and after is nil for deleted ones.
+func (s *Service) record(ctx context.Context, action EventAction, before, after *Task) error {
+	if s.events == nil {
+		return nil
+	}
+	changes := diffTasks(before, after)
+	if action == ActionUpdate && len(changes) == 0 {
+		return nil
+	}
+	event := &TaskEvent{
+		ActorID: ActorFromContext(ctx),
+		Action:  action,
+		Changes: changes,
+		Created: time.Now(),
+	}
+	if after != nil {
+		event.TaskID = after.ID
+	} else {
+		event.TaskID = before.ID
+	}
+	if err := s.events.Add(ctx, event); err != nil {
+		return fmt.Errorf("failed to record task event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type MockEventRepository struct {
+	Events []TaskEvent
+	Err    error
+}
+
+func (m *MockEventRepository) Add(ctx context.Context, event *TaskEvent) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Events = append(m.Events, *event)
+	return nil
+}
+func (m *MockEventRepository) GetByTask(ctx context.Context, taskId int) ([]TaskEvent, error) {
+	var events []TaskEvent
+	for _, e := range m.Events {
+		if e.TaskID == taskId {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+func (m *MockEventRepository) GetSince(ctx context.Context, since time.Time, limit int) ([]TaskEvent, error) {
+	return m.Events, nil
+}
+
+func TestUpdateTask_RecordsEvent(t *testing.T) {
+	mockEvents := &MockEventRepository{}
+	mockRepo := &MockRepository{TaskToReturn: &Task{ID: 1, Name: "Задача", Status: StatusNew, Created: time.Now()}}
+	service := NewService(mockRepo, nil, WithEvents(mockEvents))
+	ctx := WithActor(context.Background(), 7)
+
+	in := UpdateTaskInput{CreateTaskInput: CreateTaskInput{Name: "Задача"}, Status: StatusInProgress}
+	if _, err := service.UpdateTask(ctx, 1, in); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(mockEvents.Events) != 1 {
+		t.Fatalf("ожидалось одно событие, получено %d", len(mockEvents.Events))
+	}
+	event := mockEvents.Events[0]
+	if event.Action != ActionUpdate || event.TaskID != 1 {
+		t.Errorf("неверное событие: %+v", event)
+	}
+	if event.ActorID == nil || *event.ActorID != 7 {
+		t.Errorf("ожидался автор 7, получен %v", event.ActorID)
+	}
+	if len(event.Changes) != 1 {
+		t.Fatalf("ожидалось одно изменённое поле, получено %v", event.Changes)
+	}
+	change, ok := event.Changes["status"]
+	if !ok || change.Before != string(StatusNew) || change.After != string(StatusInProgress) {
+		t.Errorf("неверное изменение статуса: %+v", change)
+	}
+}
+
+func TestUpdateTask_NoChangesNoEvent(t *testing.T) {
+	mockEvents := &MockEventRepository{}
+	mockRepo := &MockRepository{TaskToReturn: &Task{ID: 1, Name: "Задача", Status: StatusNew, Created: time.Now()}}
+	service := NewService(mockRepo, nil, WithEvents(mockEvents))
+
+	in := UpdateTaskInput{CreateTaskInput: CreateTaskInput{Name: "Задача"}, Status: StatusNew}
+	if _, err := service.UpdateTask(context.Background(), 1, in); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(mockEvents.Events) != 0 {
+		t.Errorf("событие без изменений не должно записываться: %+v", mockEvents.Events)
+	}
+}
+
+func TestGetTaskHistory_TaskNotFound(t *testing.T) {
+	service := NewService(&MockRepository{TasksByID: map[int]*Task{}}, nil, WithEvents(&MockEventRepository{}))
+	_, err := service.GetTaskHistory(context.Background(), 1)
+	if !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("ожидалась ошибка %v, получена %v", ErrTaskNotFound, err)
+	}
+}
@@ -1,16 +1,39 @@
 package task
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Group struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Slug        string     `json:"slug"`
+	ParentID    *int       `json:"parent_id"`
+	Description string     `json:"description"`
+	Color       string     `json:"color"`
+	Icon        string     `json:"icon"`
+	OwnerID     *int       `json:"owner_id"`
+	Archived    bool       `json:"archived"`
+	WIPLimit    *int       `json:"wip_limit"`
+	WIPLoad     *int       `json:"wip_load,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Children    []Group    `json:"children,omitempty"`
 }
 
 type GroupRepository interface {
 	Add(ctx context.Context, group *Group) error
 	GetAll(ctx context.Context) ([]Group, error)
 	GetById(ctx context.Context, id int) (*Group, error)
+	GetBySlug(ctx context.Context, slug string) (*Group, error)
 	Update(ctx context.Context, group *Group) error
 	Delete(ctx context.Context, id int) error
+	GetWorkflow(ctx context.Context, groupId int) (*Workflow, error)
+	SetWorkflow(ctx context.Context, groupId int, workflow *Workflow) error
+	GetTrash(ctx context.Context, deletedBefore time.Time) ([]Group, error)
+	GetTrashed(ctx context.Context, id int) (*Group, error)
+	Restore(ctx context.Context, id int) error
+	Purge(ctx context.Context, id int) error
+	GetChildren(ctx context.Context, id int) ([]Group, error)
+	GetDescendantIDs(ctx context.Context, id int) ([]int, error)
 }
@@ -2,21 +2,126 @@ package task
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
+	"unicode/utf8"
 )
 
-func (s *Service) CreateGroup(ctx context.Context, name string) (*Group, error) {
-	name = strings.TrimSpace(name)
+var (
+	ErrGroupHasChildren    = errors.New("group has child groups")
+	ErrParentGroupNotFound = errors.New("parent group not found")
+	ErrInvalidGroupParent  = errors.New("group cannot be a child of itself or its descendants")
+
+	ErrInvalidDeleteStrategy = errors.New("invalid group delete strategy")
+	ErrTargetGroupNotFound   = errors.New("target group not found")
+
+	ErrInvalidGroupColor = errors.New("group color must be a #rrggbb hex value")
+	ErrInvalidGroupIcon  = errors.New("group icon is too long")
+	ErrGroupArchived     = errors.New("group is archived")
+)
+
+const maxGroupIconLength = 64
+
+var groupColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type GroupInput struct {
+	Name        string
+	ParentID    *int
+	Description string
+	Color       string
+	Icon        string
+	OwnerID     *int
+	Archived    bool
+	WIPLimit    *int
+}
+
+// newGroup validates the input and builds the group stored by create and
+// update.
+func (s *Service) newGroup(ctx context.Context, id int, in GroupInput) (*Group, error) {
+	name := normalizeGroupName(in.Name)
 	if name == "" {
 		return nil, ErrEmptyGroupName
 	}
-	group := &Group{
-		Name: name,
+	if in.Color != "" && !groupColorPattern.MatchString(in.Color) {
+		return nil, ErrInvalidGroupColor
+	}
+	icon := strings.TrimSpace(in.Icon)
+	if utf8.RuneCountInString(icon) > maxGroupIconLength {
+		return nil, ErrInvalidGroupIcon
+	}
+	if in.WIPLimit != nil && *in.WIPLimit <= 0 {
+		return nil, ErrInvalidWIPLimit
+	}
+	if err := s.checkGroupParent(ctx, id, in.ParentID); err != nil {
+		return nil, err
+	}
+	if err := s.checkUser(ctx, in.OwnerID); err != nil {
+		return nil, err
+	}
+	slug, err := s.groupSlug(ctx, id, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Group{
+		ID:          id,
+		Name:        name,
+		Slug:        slug,
+		ParentID:    in.ParentID,
+		Description: strings.TrimSpace(in.Description),
+		Color:       strings.ToLower(in.Color),
+		Icon:        icon,
+		OwnerID:     in.OwnerID,
+		Archived:    in.Archived,
+		WIPLimit:    in.WIPLimit,
+	}, nil
+}
+
+// groupSlug keeps the slug of an existing group unless its name changes;
+// otherwise it picks the first free one of "name", "name-2", "name-3"...
+func (s *Service) groupSlug(ctx context.Context, id int, name string) (string, error) {
+	if id != 0 {
+		current, err := s.groups.GetById(ctx, id)
+		if err != nil {
+			return "", fmt.Errorf("failed to get group: %w", err)
+		}
+		if current.Slug != "" && groupNameKey(current.Name) == groupNameKey(name) {
+			return current.Slug, nil
+		}
 	}
-	err := s.groups.Add(ctx, group)
+	base := slugify(name)
+	slug := base
+	for i := 2; ; i++ {
+		g, err := s.groups.GetBySlug(ctx, slug)
+		if errors.Is(err, ErrGroupNotFound) {
+			return slug, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to get group by slug: %w", err)
+		}
+		if g.ID == id {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+func (s *Service) CreateGroup(ctx context.Context, in GroupInput) (*Group, error) {
+	var group *Group
+	err := s.atomically(ctx, func(tx *Service) error {
+		var err error
+		if group, err = tx.newGroup(ctx, 0, in); err != nil {
+			return err
+		}
+		if err := tx.groups.Add(ctx, group); err != nil {
+			return fmt.Errorf("failed to add group: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to add group: %w", err)
+		return nil, err
 	}
 	return group, nil
 }
@@ -29,44 +134,383 @@ func (s *Service) GetGroup(ctx context.Context, id int) (*Group, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get group: %w", err)
 	}
+	load, err := wipLoad(ctx, s.repo, id)
+	if err != nil {
+		return nil, err
+	}
+	group.WIPLoad = &load
 	return group, nil
 }
 
-func (s *Service) ListGroup(ctx context.Context) ([]Group, error) {
+func (s *Service) GetGroupBySlug(ctx context.Context, slug string) (*Group, error) {
+	group, err := s.groups.GetBySlug(ctx, strings.ToLower(slug))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+	load, err := wipLoad(ctx, s.repo, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	group.WIPLoad = &load
+	return group, nil
+}
+
+// ListGroup returns the groups; archived ones only with includeArchived.
+func (s *Service) ListGroup(ctx context.Context, includeArchived bool) ([]Group, error) {
 	groups, err := s.groups.GetAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all groups: %w", err)
 	}
+	if !includeArchived {
+		groups = slices.DeleteFunc(groups, func(g Group) bool { return g.Archived })
+	}
 	return groups, nil
 }
 
-func (s *Service) UpdateGroup(ctx context.Context, id int, name string) (*Group, error) {
+// GetGroupTree returns the root groups with their descendants nested in
+// Children. Without includeArchived archived groups and their subtrees are
+// left out.
+func (s *Service) GetGroupTree(ctx context.Context, includeArchived bool) ([]Group, error) {
+	groups, err := s.ListGroup(ctx, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[int][]Group, len(groups))
+	for _, g := range groups {
+		if g.ParentID != nil {
+			children[*g.ParentID] = append(children[*g.ParentID], g)
+		}
+	}
+	var build func(g Group) Group
+	build = func(g Group) Group {
+		for _, child := range children[g.ID] {
+			g.Children = append(g.Children, build(child))
+		}
+		return g
+	}
+	roots := []Group{}
+	for _, g := range groups {
+		if g.ParentID == nil {
+			roots = append(roots, build(g))
+		}
+	}
+	return roots, nil
+}
+
+func (s *Service) GetGroupChildren(ctx context.Context, id int) ([]Group, error) {
+	if _, err := s.GetGroup(ctx, id); err != nil {
+		return nil, err
+	}
+	children, err := s.groups.GetChildren(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get child groups: %w", err)
+	}
+	return children, nil
+}
+
+func (s *Service) UpdateGroup(ctx context.Context, id int, in GroupInput) (*Group, error) {
 	if id <= 0 {
 		return nil, fmt.Errorf("incorrect id: %d", id)
 	}
-	name = strings.TrimSpace(name)
-	if name == "" {
-		return nil, ErrEmptyGroupName
+	var group *Group
+	err := s.atomically(ctx, func(tx *Service) error {
+		var err error
+		if group, err = tx.newGroup(ctx, id, in); err != nil {
+			return err
+		}
+		if err := tx.groups.Update(ctx, group); err != nil {
+			return fmt.Errorf("failed to update group: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return group, nil
+}
 
-	group := &Group{
-		ID:   id,
-		Name: name,
-	}
-	err := s.groups.Update(ctx, group)
+// SetGroupArchived archives a group or restores an archived one.
+func (s *Service) SetGroupArchived(ctx context.Context, id int, archived bool) (*Group, error) {
+	var group *Group
+	err := s.atomically(ctx, func(tx *Service) error {
+		var err error
+		if group, err = tx.GetGroup(ctx, id); err != nil {
+			return err
+		}
+		if group.Archived == archived {
+			return nil
+		}
+		group.Archived = archived
+		if err := tx.groups.Update(ctx, group); err != nil {
+			return fmt.Errorf("failed to update group: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update group: %w", err)
+		return nil, err
 	}
 	return group, nil
 }
 
-func (s *Service) DeleteGroup(ctx context.Context, id int) error {
+// checkGroup verifies that a task can be put into the group.
+func (s *Service) checkGroup(ctx context.Context, groupId *int) error {
+	if groupId == nil {
+		return nil
+	}
+	group, err := s.groups.GetById(ctx, *groupId)
+	if err != nil {
+		return fmt.Errorf("failed to get group: %w", err)
+	}
+	if group.Archived {
+		return ErrGroupArchived
+	}
+	return nil
+}
+
+type GroupDeleteStrategy string
+
+const (
+	// DeleteRestrict only deletes groups without tasks and child groups.
+	DeleteRestrict GroupDeleteStrategy = ""
+	// DeleteReassign moves the tasks to a target group.
+	DeleteReassign GroupDeleteStrategy = "reassign"
+	// DeleteOrphan leaves the tasks without a group.
+	DeleteOrphan GroupDeleteStrategy = "orphan"
+	// DeleteCascade trashes the group subtree together with its tasks.
+	DeleteCascade GroupDeleteStrategy = "cascade"
+)
+
+// DeleteGroup moves a group to the trash in a single transaction and returns
+// the number of tasks it moved or deleted. With reassign and orphan the child
+// groups are attached to the parent of the deleted group.
+func (s *Service) DeleteGroup(ctx context.Context, id int, strategy GroupDeleteStrategy, targetId *int) (int, error) {
 	if id <= 0 {
-		return fmt.Errorf("incorrect id: %d", id)
+		return 0, fmt.Errorf("incorrect id: %d", id)
+	}
+	switch strategy {
+	case DeleteReassign:
+		if targetId == nil || *targetId == id {
+			return 0, fmt.Errorf("%w: reassign needs another target group", ErrInvalidDeleteStrategy)
+		}
+	case DeleteRestrict, DeleteOrphan, DeleteCascade:
+		if targetId != nil {
+			return 0, fmt.Errorf("%w: target is only used with reassign", ErrInvalidDeleteStrategy)
+		}
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDeleteStrategy, strategy)
+	}
+
+	var affected []Task
+	err := s.atomically(ctx, func(tx *Service) error {
+		tasks, groups := tx.repo, tx.groups
+		group, err := groups.GetById(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get group: %w", err)
+		}
+		descendants, err := groups.GetDescendantIDs(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get child groups: %w", err)
+		}
+		switch strategy {
+		case DeleteRestrict:
+			if len(descendants) > 0 {
+				return ErrGroupHasChildren
+			}
+			own, err := tasks.GetAll(ctx, TaskFilter{GroupID: &id})
+			if err != nil {
+				return fmt.Errorf("failed to get group tasks: %w", err)
+			}
+			if len(own) > 0 {
+				return ErrGroupHasTasks
+			}
+		case DeleteReassign, DeleteOrphan:
+			if affected, err = reassignGroupTasks(ctx, tasks, groups, id, targetId); err != nil {
+				return err
+			}
+			if err := liftChildGroups(ctx, groups, id, group.ParentID); err != nil {
+				return err
+			}
+		case DeleteCascade:
+			ids := append([]int{id}, descendants...)
+			if affected, err = trashGroupTasks(ctx, tasks, ids); err != nil {
+				return err
+			}
+			// Children go to the trash before their parents so that purge,
+			// which runs in deletion order, never meets a referenced parent.
+			for i := len(ids) - 1; i > 0; i-- {
+				if err := groups.Delete(ctx, ids[i]); err != nil {
+					return fmt.Errorf("failed to delete group: %w", err)
+				}
+			}
+		}
+		if err := groups.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete group: %w", err)
+		}
+		for i := range affected {
+			before := affected[i]
+			if strategy == DeleteCascade {
+				err = tx.record(ctx, ActionDelete, &before, nil)
+			} else {
+				after := before
+				after.GroupID = targetId
+				err = tx.record(ctx, ActionUpdate, &before, &after)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(affected), nil
+}
+
+func reassignGroupTasks(ctx context.Context, tasks TaskRepository, groups GroupRepository, id int, targetId *int) ([]Task, error) {
+	if targetId != nil {
+		target, err := groups.GetById(ctx, *targetId)
+		if err != nil {
+			if errors.Is(err, ErrGroupNotFound) {
+				return nil, ErrTargetGroupNotFound
+			}
+			return nil, fmt.Errorf("failed to get target group: %w", err)
+		}
+		if target.Archived {
+			return nil, ErrGroupArchived
+		}
+	}
+	moved, err := tasks.GetAll(ctx, TaskFilter{GroupID: &id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group tasks: %w", err)
+	}
+	if err := checkMovedTasks(ctx, tasks, groups, targetId, moved); err != nil {
+		return nil, err
+	}
+	if _, err := tasks.ReassignGroup(ctx, id, targetId); err != nil {
+		return nil, fmt.Errorf("failed to reassign group tasks: %w", err)
+	}
+	return moved, nil
+}
+
+func liftChildGroups(ctx context.Context, groups GroupRepository, id int, parentId *int) error {
+	children, err := groups.GetChildren(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get child groups: %w", err)
+	}
+	for _, child := range children {
+		child.ParentID = parentId
+		if err := groups.Update(ctx, &child); err != nil {
+			return fmt.Errorf("failed to move child group %d: %w", child.ID, err)
+		}
+	}
+	return nil
+}
+
+// trashGroupTasks moves all tasks of the given groups to the trash, subtasks
+// before their parents. Subtasks living in other groups block the deletion.
+func trashGroupTasks(ctx context.Context, tasks TaskRepository, groupIds []int) ([]Task, error) {
+	list, err := tasks.GetAll(ctx, TaskFilter{GroupIDs: groupIds})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group tasks: %w", err)
+	}
+	parents := make(map[int]*int, len(list))
+	for _, t := range list {
+		parents[t.ID] = t.ParentID
+	}
+	for _, t := range list {
+		children, err := tasks.GetAll(ctx, TaskFilter{ParentID: &t.ID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get subtasks: %w", err)
+		}
+		for _, child := range children {
+			if _, ok := parents[child.ID]; !ok {
+				return nil, fmt.Errorf("%w: task %d has subtask %d in another group", ErrTaskHasSubtasks, t.ID, child.ID)
+			}
+		}
+	}
+	depth := make(map[int]int, len(list))
+	for _, t := range list {
+		for p := t.ParentID; p != nil; p = parents[*p] {
+			if _, ok := parents[*p]; !ok {
+				break
+			}
+			depth[t.ID]++
+		}
+	}
+	slices.SortStableFunc(list, func(a, b Task) int { return depth[b.ID] - depth[a.ID] })
+	for _, t := range list {
+		if err := tasks.Delete(ctx, t.ID, t.Version); err != nil {
+			return nil, fmt.Errorf("failed to delete task %d: %w", t.ID, err)
+		}
+	}
+	return list, nil
+}
+
+// checkGroupParent verifies that parentId exists and is not the group itself
+// or one of its descendants; id is 0 for a new group.
+func (s *Service) checkGroupParent(ctx context.Context, id int, parentId *int) error {
+	if parentId == nil {
+		return nil
+	}
+	if *parentId == id {
+		return ErrInvalidGroupParent
+	}
+	if _, err := s.groups.GetById(ctx, *parentId); err != nil {
+		if errors.Is(err, ErrGroupNotFound) {
+			return ErrParentGroupNotFound
+		}
+		return fmt.Errorf("failed to get parent group: %w", err)
 	}
-	err := s.groups.Delete(ctx, id)
+	if id == 0 {
+		return nil
+	}
+	descendants, err := s.groups.GetDescendantIDs(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete group: %w", err)
+		return fmt.Errorf("failed to get child groups: %w", err)
+	}
+	if slices.Contains(descendants, *parentId) {
+		return ErrInvalidGroupParent
 	}
 	return nil
 }
+
+func (s *Service) GetWorkflow(ctx context.Context, groupId int) (*Workflow, error) {
+	if groupId <= 0 {
+		return nil, fmt.Errorf("incorrect id: %d", groupId)
+	}
+	return s.workflowFor(ctx, &groupId)
+}
+
+// SetWorkflow replaces the group workflow; nil resets it to DefaultWorkflow.
+func (s *Service) SetWorkflow(ctx context.Context, groupId int, workflow *Workflow) (*Workflow, error) {
+	if groupId <= 0 {
+		return nil, fmt.Errorf("incorrect id: %d", groupId)
+	}
+	effective := workflow
+	if effective == nil {
+		effective = DefaultWorkflow()
+	}
+	if err := effective.Validate(); err != nil {
+		return nil, err
+	}
+	err := s.withTx(ctx, func(tasks TaskRepository, groups GroupRepository) error {
+		list, err := tasks.GetAll(ctx, TaskFilter{GroupID: &groupId})
+		if err != nil {
+			return fmt.Errorf("failed to get group tasks: %w", err)
+		}
+		for _, t := range list {
+			if !effective.HasState(t.Status) {
+				return fmt.Errorf("%w: state %q is used by task %d", ErrInvalidWorkflow, t.Status, t.ID)
+			}
+		}
+		if err := groups.SetWorkflow(ctx, groupId, workflow); err != nil {
+			return fmt.Errorf("failed to set group workflow: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return effective, nil
+}
@@ -0,0 +1,197 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGetGroupTree(t *testing.T) {
+	root, child := 1, 2
+	mockGroupRepo := &MockGroupRepository{GroupsToReturn: []Group{
+		{ID: 1, Name: "Работа"},
+		{ID: 2, Name: "Проекты", ParentID: &root},
+		{ID: 3, Name: "Бэкенд", ParentID: &child},
+		{ID: 4, Name: "Дом"},
+	}}
+	service := NewService(&MockRepository{}, mockGroupRepo)
+	tree, err := service.GetGroupTree(context.Background(), false)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(tree) != 2 {
+		t.Fatalf("ожидалось 2 корневые группы, получено %d", len(tree))
+	}
+	if len(tree[0].Children) != 1 || len(tree[0].Children[0].Children) != 1 {
+		t.Fatalf("неверная вложенность: %+v", tree[0])
+	}
+	if tree[0].Children[0].Children[0].ID != 3 {
+		t.Errorf("ожидалась группа 3, получена %d", tree[0].Children[0].Children[0].ID)
+	}
+}
+
+func TestDeleteGroup_Strategies(t *testing.T) {
+	groupID, targetID := 1, 5
+	parentID, taskID := 10, 7
+	tests := []struct {
+		name           string
+		strategy       GroupDeleteStrategy
+		target         *int
+		tasks          []Task
+		expectedErr    error
+		expectedCount  int
+		deletedGroups  []int
+		deletedTasks   []int
+		reassigned     bool
+		expectedTarget *int
+	}{
+		{name: "Ошибка: есть дочерние группы", expectedErr: ErrGroupHasChildren},
+		{name: "Ошибка: неизвестная стратегия", strategy: "drop", expectedErr: ErrInvalidDeleteStrategy},
+		{name: "Ошибка: reassign без цели", strategy: DeleteReassign, expectedErr: ErrInvalidDeleteStrategy},
+		{name: "Ошибка: цель у orphan", strategy: DeleteOrphan, target: &targetID, expectedErr: ErrInvalidDeleteStrategy},
+		{
+			name:           "Перенос задач в другую группу",
+			strategy:       DeleteReassign,
+			target:         &targetID,
+			tasks:          []Task{{ID: 7, GroupID: &groupID, Status: StatusNew}},
+			expectedCount:  1,
+			deletedGroups:  []int{1},
+			reassigned:     true,
+			expectedTarget: &targetID,
+		},
+		{
+			name:          "Задачи без группы",
+			strategy:      DeleteOrphan,
+			tasks:         []Task{{ID: 7, GroupID: &groupID, Status: StatusNew}},
+			expectedCount: 1,
+			deletedGroups: []int{1},
+			reassigned:    true,
+		},
+		{
+			name:        "Ошибка: статус вне процесса без группы",
+			strategy:    DeleteOrphan,
+			tasks:       []Task{{ID: 7, GroupID: &groupID, Status: "review"}},
+			expectedErr: ErrInvalidStatus,
+		},
+		{
+			name:          "Каскадное удаление",
+			strategy:      DeleteCascade,
+			tasks:         []Task{{ID: 7, GroupID: &groupID}, {ID: 8, GroupID: &groupID, ParentID: &taskID}},
+			expectedCount: 2,
+			deletedGroups: []int{3, 2, 1},
+			deletedTasks:  []int{8, 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{TasksToReturn: tt.tasks}
+			mockGroupRepo := &MockGroupRepository{
+				GroupToReturn: &Group{ID: groupID, ParentID: &parentID},
+				DescendantIDs: []int{2, 3},
+			}
+			service := NewService(mockRepo, mockGroupRepo)
+			count, err := service.DeleteGroup(context.Background(), groupID, tt.strategy, tt.target)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if count != tt.expectedCount {
+				t.Errorf("затронуто задач %d, ожидалось %d", count, tt.expectedCount)
+			}
+			if !slices.Equal(mockGroupRepo.DeletedIDs, tt.deletedGroups) {
+				t.Errorf("удалены группы %v, ожидалось %v", mockGroupRepo.DeletedIDs, tt.deletedGroups)
+			}
+			if !slices.Equal(mockRepo.DeletedIDs, tt.deletedTasks) {
+				t.Errorf("удалены задачи %v, ожидалось %v", mockRepo.DeletedIDs, tt.deletedTasks)
+			}
+			if mockRepo.ReassignCalled != tt.reassigned || !sameID(mockRepo.ReassignedTo, tt.expectedTarget) {
+				t.Errorf("перенос задач: вызван %v в группу %v", mockRepo.ReassignCalled, mockRepo.ReassignedTo)
+			}
+		})
+	}
+}
+
+func TestUpdateGroup_ParentCycle(t *testing.T) {
+	self, descendant := 1, 3
+	for _, parentId := range []*int{&self, &descendant} {
+		mockGroupRepo := &MockGroupRepository{DescendantIDs: []int{2, 3}}
+		service := NewService(&MockRepository{}, mockGroupRepo)
+		_, err := service.UpdateGroup(context.Background(), 1, GroupInput{Name: "Работа", ParentID: parentId})
+		if !errors.Is(err, ErrInvalidGroupParent) {
+			t.Errorf("родитель %d: ожидалась ошибка %v, получена %v", *parentId, ErrInvalidGroupParent, err)
+		}
+	}
+}
+
+func TestGetAllTasks_Recursive(t *testing.T) {
+	groupID := 1
+	mockRepo := &MockRepository{}
+	service := NewService(mockRepo, &MockGroupRepository{DescendantIDs: []int{2, 3}})
+	_, err := service.GetAllTasks(context.Background(), TaskFilter{GroupID: &groupID, Recursive: true})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	filter := mockRepo.GetAllCalledWith
+	if filter.GroupID != nil || !slices.Equal(filter.GroupIDs, []int{1, 2, 3}) {
+		t.Errorf("ожидались группы [1 2 3], получен фильтр %+v", filter)
+	}
+}
+
+func TestListGroup_HidesArchived(t *testing.T) {
+	mockGroupRepo := &MockGroupRepository{GroupsToReturn: []Group{
+		{ID: 1, Name: "Работа"},
+		{ID: 2, Name: "Старое", Archived: true},
+	}}
+	service := NewService(&MockRepository{}, mockGroupRepo)
+	groups, err := service.ListGroup(context.Background(), false)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(groups) != 1 || groups[0].ID != 1 {
+		t.Errorf("архивная группа не должна попадать в список: %+v", groups)
+	}
+	groups, _ = service.ListGroup(context.Background(), true)
+	if len(groups) != 2 {
+		t.Errorf("ожидалось 2 группы с архивными, получено %d", len(groups))
+	}
+}
+
+func TestCreateTask_ArchivedGroup(t *testing.T) {
+	groupID := 2
+	mockRepo := &MockRepository{}
+	mockGroupRepo := &MockGroupRepository{GroupToReturn: &Group{ID: groupID, Name: "Старое", Archived: true}}
+	service := NewService(mockRepo, mockGroupRepo)
+	_, err := service.CreateTask(context.Background(), CreateTaskInput{Name: "Задача", GroupID: &groupID})
+	if !errors.Is(err, ErrGroupArchived) {
+		t.Fatalf("ожидалась ошибка %v, получена %v", ErrGroupArchived, err)
+	}
+	if mockRepo.AddCalled {
+		t.Error("задача не должна добавляться в архивную группу")
+	}
+}
+
+func TestCreateGroup_Metadata(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          GroupInput
+		expectedErr error
+	}{
+		{name: "Успешное создание", in: GroupInput{Name: "Работа", Color: "#FF8800", Icon: "briefcase"}},
+		{name: "Ошибка: неверный цвет", in: GroupInput{Name: "Работа", Color: "orange"}, expectedErr: ErrInvalidGroupColor},
+		{name: "Ошибка: длинная иконка", in: GroupInput{Name: "Работа", Icon: strings.Repeat("x", 65)}, expectedErr: ErrInvalidGroupIcon},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGroupRepo := &MockGroupRepository{}
+			service := NewService(&MockRepository{}, mockGroupRepo)
+			g, err := service.CreateGroup(context.Background(), tt.in)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if err == nil && g.Color != "#ff8800" {
+				t.Errorf("ожидался цвет #ff8800, получен %q", g.Color)
+			}
+		})
+	}
+}
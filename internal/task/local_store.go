@@ -0,0 +1,80 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type LocalAttachmentStore struct {
+	dir string
+}
+
+func NewLocalAttachmentStore(dir string) (*LocalAttachmentStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create attachments dir: %w", err)
+	}
+	return &LocalAttachmentStore{
+		dir: dir,
+	}, nil
+}
+
+func (s *LocalAttachmentStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("local.Put: create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return n, fmt.Errorf("local.Put: write %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return n, fmt.Errorf("local.Put: rename %s: %w", key, err)
+	}
+	return n, nil
+}
+
+func (s *LocalAttachmentStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("local.Open %s: %w", key, err)
+	}
+	return f, nil
+}
+
+func (s *LocalAttachmentStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("local.Delete %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *LocalAttachmentStore) path(key string) (string, error) {
+	if key == "" || filepath.Base(key) != key || key[0] == '.' {
+		return "", fmt.Errorf("local: invalid attachment key %q", key)
+	}
+	return filepath.Join(s.dir, key), nil
+}
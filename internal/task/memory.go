@@ -0,0 +1,482 @@
+package task
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+type MemoryRepository struct {
+	memoryConn
+}
+
+func NewMemoryRepository(db *MemoryDB) *MemoryRepository {
+	return &MemoryRepository{memoryConn{db: db}}
+}
+
+// storedTask drops the derived fields, which the Postgres repository does not
+// store either, and copies the pointers so callers cannot change stored data.
+func storedTask(t *Task) Task {
+	return Task{
+		ID:              t.ID,
+		Name:            t.Name,
+		Description:     t.Description,
+		Created:         t.Created,
+		Status:          t.Status,
+		Priority:        t.Priority,
+		GroupID:         clonePtr(t.GroupID),
+		StartAt:         clonePtr(t.StartAt),
+		DueAt:           clonePtr(t.DueAt),
+		ParentID:        clonePtr(t.ParentID),
+		AssigneeID:      clonePtr(t.AssigneeID),
+		Recurrence:      clonePtr(t.Recurrence),
+		RecurrenceStart: clonePtr(t.RecurrenceStart),
+		Rank:            t.Rank,
+		CompletedAt:     clonePtr(t.CompletedAt),
+		Version:         t.Version,
+		DeletedAt:       clonePtr(t.DeletedAt),
+	}
+}
+
+// loadTask returns a copy of a stored task with the group name joined in.
+func (r *MemoryRepository) loadTask(t Task) Task {
+	t = storedTask(&t)
+	if t.GroupID != nil {
+		if g, ok := r.db.groups[*t.GroupID]; ok {
+			t.GroupName = clonePtr(&g.Name)
+		}
+	}
+	return t
+}
+
+// checkTaskRefs mirrors the foreign keys of the tasks table, which do not
+// care whether the referenced rows are in the trash.
+func (r *MemoryRepository) checkTaskRefs(t *Task) error {
+	if t.GroupID != nil {
+		if _, ok := r.db.groups[*t.GroupID]; !ok {
+			return ErrGroupNotFound
+		}
+	}
+	if t.ParentID != nil {
+		if _, ok := r.db.tasks[*t.ParentID]; !ok {
+			return ErrParentNotFound
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) columnEndRank(groupId *int, status TaskStatus) float64 {
+	var last float64
+	for _, t := range r.db.tasks {
+		if t.DeletedAt == nil && sameID(t.GroupID, groupId) && t.Status == status && t.Rank > last {
+			last = t.Rank
+		}
+	}
+	return last + rankStep
+}
+
+func (r *MemoryRepository) Add(ctx context.Context, task *Task) error {
+	defer r.lock()()
+	if err := r.checkTaskRefs(task); err != nil {
+		return err
+	}
+	r.db.nextTaskID++
+	task.ID = r.db.nextTaskID
+	task.Rank = r.columnEndRank(task.GroupID, task.Status)
+	task.Version = 1
+	stored := storedTask(task)
+	stored.DeletedAt = nil
+	r.db.tasks[task.ID] = stored
+	return nil
+}
+
+func (r *MemoryRepository) GetById(ctx context.Context, id int) (*Task, error) {
+	defer r.rlock()()
+	t, ok := r.db.tasks[id]
+	if !ok || t.DeletedAt != nil {
+		return nil, ErrTaskNotFound
+	}
+	t = r.loadTask(t)
+	return &t, nil
+}
+
+func (r *MemoryRepository) matchTask(t Task, filter TaskFilter, now time.Time) bool {
+	if t.DeletedAt != nil {
+		return false
+	}
+	if filter.GroupID != nil && !sameID(t.GroupID, filter.GroupID) {
+		return false
+	}
+	if len(filter.GroupIDs) > 0 && (t.GroupID == nil || !slices.Contains(filter.GroupIDs, *t.GroupID)) {
+		return false
+	}
+	if filter.Status != nil && t.Status != *filter.Status {
+		return false
+	}
+	if filter.ParentID != nil && !sameID(t.ParentID, filter.ParentID) {
+		return false
+	}
+	if filter.AssigneeID != nil && !sameID(t.AssigneeID, filter.AssigneeID) {
+		return false
+	}
+	if filter.DueBefore != nil && (t.DueAt == nil || !t.DueAt.Before(*filter.DueBefore)) {
+		return false
+	}
+	if filter.DueAfter != nil && (t.DueAt == nil || !t.DueAt.After(*filter.DueAfter)) {
+		return false
+	}
+	if filter.Overdue && (t.DueAt == nil || !t.DueAt.Before(now) || t.CompletedAt != nil) {
+		return false
+	}
+	// Tags are kept by the tag repository, which has no memory implementation,
+	// so no task carries any tag.
+	return len(filter.Tags) == 0
+}
+
+func (r *MemoryRepository) Count(ctx context.Context, filter TaskFilter) (int, error) {
+	defer r.rlock()()
+	now := time.Now()
+	count := 0
+	for _, t := range r.db.tasks {
+		if r.matchTask(t, filter, now) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryRepository) GetAll(ctx context.Context, filter TaskFilter) ([]Task, error) {
+	defer r.rlock()()
+	now := time.Now()
+	tasks := []Task{}
+	for _, t := range r.db.tasks {
+		if r.matchTask(t, filter, now) {
+			tasks = append(tasks, r.loadTask(t))
+		}
+	}
+	sortTasks(tasks, filter.Sort)
+	return tasks, nil
+}
+
+var priorityOrder = map[TaskPriority]int{PriorityLow: 0, PriorityNormal: 1, PriorityHigh: 2, PriorityUrgent: 3}
+
+// sortTasks orders tasks the way taskOrder does in SQL: empty values last in
+// both directions and the id as the final tiebreaker.
+func sortTasks(tasks []Task, sort []SortField) {
+	if len(sort) == 0 {
+		sort = []SortField{{Field: "created"}}
+	}
+	slices.SortFunc(tasks, func(a, b Task) int {
+		for _, f := range sort {
+			c, nulls := compareTaskField(a, b, f.Field)
+			if c == 0 {
+				continue
+			}
+			if f.Desc && !nulls {
+				c = -c
+			}
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+// compareTaskField compares one sort field; nulls reports that the result
+// only puts an empty value last and must not be reversed.
+func compareTaskField(a, b Task, field string) (int, bool) {
+	switch field {
+	case "id":
+		return cmp.Compare(a.ID, b.ID), false
+	case "name":
+		return strings.Compare(a.Name, b.Name), false
+	case "created":
+		return a.Created.Compare(b.Created), false
+	case "status":
+		return strings.Compare(string(a.Status), string(b.Status)), false
+	case "priority":
+		return cmp.Compare(priorityOrder[a.Priority], priorityOrder[b.Priority]), false
+	case "start_at":
+		return compareTimes(a.StartAt, b.StartAt)
+	case "due_at":
+		return compareTimes(a.DueAt, b.DueAt)
+	case "rank":
+		return cmp.Compare(a.Rank, b.Rank), false
+	}
+	return 0, false
+}
+
+func compareTimes(a, b *time.Time) (int, bool) {
+	switch {
+	case a == nil && b == nil:
+		return 0, false
+	case a == nil:
+		return 1, true
+	case b == nil:
+		return -1, true
+	}
+	return a.Compare(*b), false
+}
+
+func (r *MemoryRepository) Update(ctx context.Context, task *Task) error {
+	defer r.lock()()
+	current, ok := r.db.tasks[task.ID]
+	if !ok || current.DeletedAt != nil {
+		return ErrTaskNotFound
+	}
+	if current.Version != task.Version {
+		return ErrConflict
+	}
+	if err := r.checkTaskRefs(task); err != nil {
+		return err
+	}
+	rank := current.Rank
+	if current.Status != task.Status || !sameID(current.GroupID, task.GroupID) {
+		rank = r.columnEndRank(task.GroupID, task.Status)
+	}
+	updated := storedTask(task)
+	updated.Created = current.Created
+	updated.Rank = rank
+	updated.Version = current.Version + 1
+	updated.DeletedAt = nil
+	r.db.tasks[task.ID] = updated
+	task.Rank = rank
+	task.Version = updated.Version
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, id, version int) error {
+	defer r.lock()()
+	t, ok := r.db.tasks[id]
+	if !ok || t.DeletedAt != nil {
+		return ErrTaskNotFound
+	}
+	if t.Version != version {
+		return ErrConflict
+	}
+	now := time.Now()
+	t.DeletedAt = &now
+	t.Version++
+	r.db.tasks[id] = t
+	return nil
+}
+
+func (r *MemoryRepository) GetTrash(ctx context.Context, deletedBefore time.Time) ([]Task, error) {
+	defer r.rlock()()
+	tasks := []Task{}
+	for _, t := range r.db.tasks {
+		if t.DeletedAt != nil && t.DeletedAt.Before(deletedBefore) {
+			tasks = append(tasks, r.loadTask(t))
+		}
+	}
+	slices.SortFunc(tasks, func(a, b Task) int {
+		return cmp.Or(a.DeletedAt.Compare(*b.DeletedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return tasks, nil
+}
+
+func (r *MemoryRepository) GetTrashed(ctx context.Context, id int) (*Task, error) {
+	defer r.rlock()()
+	t, ok := r.db.tasks[id]
+	if !ok || t.DeletedAt == nil {
+		return nil, ErrTaskNotFound
+	}
+	t = r.loadTask(t)
+	return &t, nil
+}
+
+func (r *MemoryRepository) Restore(ctx context.Context, id int) error {
+	defer r.lock()()
+	t, ok := r.db.tasks[id]
+	if !ok || t.DeletedAt == nil {
+		return ErrTaskNotFound
+	}
+	t.DeletedAt = nil
+	t.Version++
+	r.db.tasks[id] = t
+	return nil
+}
+
+func (r *MemoryRepository) Purge(ctx context.Context, id int) error {
+	defer r.lock()()
+	t, ok := r.db.tasks[id]
+	if !ok || t.DeletedAt == nil {
+		return ErrTaskNotFound
+	}
+	for _, other := range r.db.tasks {
+		if other.ParentID != nil && *other.ParentID == id {
+			return ErrTaskHasSubtasks
+		}
+	}
+	delete(r.db.tasks, id)
+	return nil
+}
+
+func (r *MemoryRepository) Move(ctx context.Context, id int, afterId, beforeId *int) (float64, error) {
+	defer r.lock()()
+	t, ok := r.db.tasks[id]
+	if !ok || t.DeletedAt != nil {
+		return 0, ErrTaskNotFound
+	}
+	rank, ok, err := r.moveRank(t, afterId, beforeId)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		r.rebalanceColumn(t.GroupID, t.Status)
+		if rank, _, err = r.moveRank(t, afterId, beforeId); err != nil {
+			return 0, err
+		}
+	}
+	t = r.db.tasks[id]
+	t.Rank = rank
+	t.Version++
+	r.db.tasks[id] = t
+	return rank, nil
+}
+
+// column returns the live tasks of a board column except the given one.
+func (r *MemoryRepository) column(groupId *int, status TaskStatus, except int) []Task {
+	var tasks []Task
+	for _, t := range r.db.tasks {
+		if t.DeletedAt == nil && sameID(t.GroupID, groupId) && t.Status == status && t.ID != except {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+func (r *MemoryRepository) neighborRank(id int) (float64, error) {
+	t, ok := r.db.tasks[id]
+	if !ok || t.DeletedAt != nil {
+		return 0, ErrTaskNotFound
+	}
+	return t.Rank, nil
+}
+
+// moveRank follows moveRank of the Postgres repository.
+func (r *MemoryRepository) moveRank(t Task, afterId, beforeId *int) (float64, bool, error) {
+	var lower, upper *float64
+	if afterId != nil {
+		rank, err := r.neighborRank(*afterId)
+		if err != nil {
+			return 0, false, err
+		}
+		lower = &rank
+	}
+	if beforeId != nil {
+		rank, err := r.neighborRank(*beforeId)
+		if err != nil {
+			return 0, false, err
+		}
+		upper = &rank
+	}
+	switch {
+	case afterId != nil && beforeId == nil:
+		for _, c := range r.column(t.GroupID, t.Status, t.ID) {
+			if c.Rank > *lower && (upper == nil || c.Rank < *upper) {
+				upper = &c.Rank
+			}
+		}
+	case beforeId != nil && afterId == nil:
+		for _, c := range r.column(t.GroupID, t.Status, t.ID) {
+			if c.Rank < *upper && (lower == nil || c.Rank > *lower) {
+				lower = &c.Rank
+			}
+		}
+	}
+
+	switch {
+	case lower != nil && upper != nil:
+		if *upper-*lower < rankEpsilon {
+			return 0, false, nil
+		}
+		return (*lower + *upper) / 2, true, nil
+	case lower != nil:
+		return *lower + rankStep, true, nil
+	case upper != nil:
+		return *upper - rankStep, true, nil
+	}
+	return 0, true, nil
+}
+
+func (r *MemoryRepository) rebalanceColumn(groupId *int, status TaskStatus) {
+	tasks := r.column(groupId, status, 0)
+	slices.SortFunc(tasks, func(a, b Task) int {
+		return cmp.Or(cmp.Compare(a.Rank, b.Rank), cmp.Compare(a.ID, b.ID))
+	})
+	for i, t := range tasks {
+		t.Rank = float64(i+1) * rankStep
+		t.Version++
+		r.db.tasks[t.ID] = t
+	}
+}
+
+func (r *MemoryRepository) ReassignGroup(ctx context.Context, fromGroupId int, toGroupId *int) (int, error) {
+	defer r.lock()()
+	if toGroupId != nil {
+		if _, ok := r.db.groups[*toGroupId]; !ok {
+			return 0, ErrGroupNotFound
+		}
+	}
+	moved := 0
+	for id, t := range r.db.tasks {
+		if t.DeletedAt == nil && t.GroupID != nil && *t.GroupID == fromGroupId {
+			t.GroupID = clonePtr(toGroupId)
+			t.Version++
+			r.db.tasks[id] = t
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+func (r *MemoryRepository) GetStats(ctx context.Context, filter StatsFilter) (*TaskStats, error) {
+	defer r.rlock()()
+	loc := filter.From.Location()
+	day := func(t time.Time) time.Time {
+		y, m, d := t.In(loc).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+	from, to := day(filter.From), day(filter.To)
+	stats := &TaskStats{ByStatus: map[TaskStatus]int{}, From: filter.From, To: filter.To}
+	daily := map[time.Time]*DailyStats{}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		stats.Daily = append(stats.Daily, DailyStats{Day: d})
+	}
+	for i := range stats.Daily {
+		daily[stats.Daily[i].Day] = &stats.Daily[i]
+	}
+
+	var openAge time.Duration
+	for _, t := range r.db.tasks {
+		if !r.matchTask(t, TaskFilter{GroupIDs: filter.GroupIDs}, filter.Now) {
+			continue
+		}
+		stats.ByStatus[t.Status]++
+		stats.Total++
+		if t.CompletedAt == nil {
+			stats.Open++
+			openAge += filter.Now.Sub(t.Created)
+			if t.DueAt != nil && t.DueAt.Before(filter.Now) {
+				stats.Overdue++
+			}
+		}
+		if d, ok := daily[day(t.Created)]; ok {
+			d.Created++
+		}
+		if t.CompletedAt != nil {
+			if d, ok := daily[day(*t.CompletedAt)]; ok {
+				d.Completed++
+			}
+		}
+	}
+	if stats.Open > 0 {
+		stats.AvgOpenAge = openAge.Hours() / float64(stats.Open)
+	}
+	if stats.Daily == nil {
+		stats.Daily = []DailyStats{}
+	}
+	return stats, nil
+}
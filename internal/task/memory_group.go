@@ -0,0 +1,274 @@
+package task
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type MemoryGroupRepository struct {
+	memoryConn
+}
+
+func NewMemoryGroupRepository(db *MemoryDB) *MemoryGroupRepository {
+	return &MemoryGroupRepository{memoryConn{db: db}}
+}
+
+func storedGroup(g *Group) Group {
+	return Group{
+		ID:          g.ID,
+		Name:        g.Name,
+		Slug:        g.Slug,
+		ParentID:    clonePtr(g.ParentID),
+		Description: g.Description,
+		Color:       g.Color,
+		Icon:        g.Icon,
+		OwnerID:     clonePtr(g.OwnerID),
+		Archived:    g.Archived,
+		WIPLimit:    clonePtr(g.WIPLimit),
+		DeletedAt:   clonePtr(g.DeletedAt),
+	}
+}
+
+// checkGroupUnique mirrors the unique indexes on live groups: the name key
+// among siblings and the slug.
+func (r *MemoryGroupRepository) checkGroupUnique(g Group) error {
+	key := groupNameKey(g.Name)
+	for _, other := range r.db.groups {
+		if other.ID == g.ID || other.DeletedAt != nil {
+			continue
+		}
+		if other.Slug == g.Slug || (sameID(other.ParentID, g.ParentID) && groupNameKey(other.Name) == key) {
+			return ErrNotUniqGroup
+		}
+	}
+	return nil
+}
+
+func (r *MemoryGroupRepository) checkGroupRefs(g *Group) error {
+	if g.ParentID != nil {
+		if _, ok := r.db.groups[*g.ParentID]; !ok {
+			return ErrParentGroupNotFound
+		}
+	}
+	return nil
+}
+
+func (r *MemoryGroupRepository) Add(ctx context.Context, group *Group) error {
+	defer r.lock()()
+	stored := storedGroup(group)
+	stored.DeletedAt = nil
+	if err := r.checkGroupUnique(stored); err != nil {
+		return err
+	}
+	if err := r.checkGroupRefs(group); err != nil {
+		return err
+	}
+	r.db.nextGroupID++
+	group.ID = r.db.nextGroupID
+	stored.ID = group.ID
+	r.db.groups[group.ID] = memoryGroup{Group: stored}
+	return nil
+}
+
+func (r *MemoryGroupRepository) GetById(ctx context.Context, id int) (*Group, error) {
+	defer r.rlock()()
+	g, ok := r.db.groups[id]
+	if !ok || g.DeletedAt != nil {
+		return nil, ErrGroupNotFound
+	}
+	group := storedGroup(&g.Group)
+	return &group, nil
+}
+
+func (r *MemoryGroupRepository) GetBySlug(ctx context.Context, slug string) (*Group, error) {
+	defer r.rlock()()
+	for _, g := range r.db.groups {
+		if g.DeletedAt == nil && g.Slug == slug {
+			group := storedGroup(&g.Group)
+			return &group, nil
+		}
+	}
+	return nil, ErrGroupNotFound
+}
+
+// list returns the copies of the groups matching keep ordered by name and id.
+func (r *MemoryGroupRepository) list(keep func(g Group) bool) []Group {
+	var groups []Group
+	for _, g := range r.db.groups {
+		if keep(g.Group) {
+			groups = append(groups, storedGroup(&g.Group))
+		}
+	}
+	slices.SortFunc(groups, func(a, b Group) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+	return groups
+}
+
+func (r *MemoryGroupRepository) GetAll(ctx context.Context) ([]Group, error) {
+	defer r.rlock()()
+	return r.list(func(g Group) bool { return g.DeletedAt == nil }), nil
+}
+
+func (r *MemoryGroupRepository) Update(ctx context.Context, group *Group) error {
+	defer r.lock()()
+	current, ok := r.db.groups[group.ID]
+	if !ok || current.DeletedAt != nil {
+		return ErrGroupNotFound
+	}
+	updated := storedGroup(group)
+	updated.DeletedAt = nil
+	if err := r.checkGroupUnique(updated); err != nil {
+		return err
+	}
+	if err := r.checkGroupRefs(group); err != nil {
+		return err
+	}
+	r.db.groups[group.ID] = memoryGroup{Group: updated, workflow: current.workflow}
+	return nil
+}
+
+func (r *MemoryGroupRepository) Delete(ctx context.Context, id int) error {
+	defer r.lock()()
+	g, ok := r.db.groups[id]
+	if !ok || g.DeletedAt != nil {
+		return ErrGroupNotFound
+	}
+	now := time.Now()
+	g.DeletedAt = &now
+	r.db.groups[id] = g
+	return nil
+}
+
+func (r *MemoryGroupRepository) GetWorkflow(ctx context.Context, groupId int) (*Workflow, error) {
+	defer r.rlock()()
+	g, ok := r.db.groups[groupId]
+	if !ok {
+		return nil, ErrGroupNotFound
+	}
+	if g.workflow == nil {
+		return nil, nil
+	}
+	return cloneWorkflow(g.workflow)
+}
+
+func (r *MemoryGroupRepository) SetWorkflow(ctx context.Context, groupId int, workflow *Workflow) error {
+	defer r.lock()()
+	g, ok := r.db.groups[groupId]
+	if !ok || g.DeletedAt != nil {
+		return ErrGroupNotFound
+	}
+	g.workflow = nil
+	if workflow != nil {
+		stored, err := cloneWorkflow(workflow)
+		if err != nil {
+			return err
+		}
+		g.workflow = stored
+	}
+	r.db.groups[groupId] = g
+	return nil
+}
+
+// cloneWorkflow copies a workflow through JSON, as it is stored in Postgres.
+func cloneWorkflow(workflow *Workflow) (*Workflow, error) {
+	data, err := json.Marshal(workflow)
+	if err != nil {
+		return nil, fmt.Errorf("memory: encode workflow: %w", err)
+	}
+	var clone Workflow
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, fmt.Errorf("memory: decode workflow: %w", err)
+	}
+	return &clone, nil
+}
+
+func (r *MemoryGroupRepository) GetTrash(ctx context.Context, deletedBefore time.Time) ([]Group, error) {
+	defer r.rlock()()
+	groups := r.list(func(g Group) bool { return g.DeletedAt != nil && g.DeletedAt.Before(deletedBefore) })
+	slices.SortFunc(groups, func(a, b Group) int {
+		return cmp.Or(a.DeletedAt.Compare(*b.DeletedAt), cmp.Compare(a.ID, b.ID))
+	})
+	if groups == nil {
+		groups = []Group{}
+	}
+	return groups, nil
+}
+
+func (r *MemoryGroupRepository) GetTrashed(ctx context.Context, id int) (*Group, error) {
+	defer r.rlock()()
+	g, ok := r.db.groups[id]
+	if !ok || g.DeletedAt == nil {
+		return nil, ErrGroupNotFound
+	}
+	group := storedGroup(&g.Group)
+	return &group, nil
+}
+
+func (r *MemoryGroupRepository) Restore(ctx context.Context, id int) error {
+	defer r.lock()()
+	g, ok := r.db.groups[id]
+	if !ok || g.DeletedAt == nil {
+		return ErrGroupNotFound
+	}
+	g.DeletedAt = nil
+	if err := r.checkGroupUnique(g.Group); err != nil {
+		return err
+	}
+	r.db.groups[id] = g
+	return nil
+}
+
+func (r *MemoryGroupRepository) Purge(ctx context.Context, id int) error {
+	defer r.lock()()
+	g, ok := r.db.groups[id]
+	if !ok || g.DeletedAt == nil {
+		return ErrGroupNotFound
+	}
+	for _, t := range r.db.tasks {
+		if t.GroupID != nil && *t.GroupID == id {
+			return ErrGroupHasTasks
+		}
+	}
+	for _, child := range r.db.groups {
+		if child.ParentID != nil && *child.ParentID == id {
+			return ErrGroupHasTasks
+		}
+	}
+	delete(r.db.groups, id)
+	return nil
+}
+
+func (r *MemoryGroupRepository) GetChildren(ctx context.Context, id int) ([]Group, error) {
+	defer r.rlock()()
+	groups := r.list(func(g Group) bool {
+		return g.DeletedAt == nil && g.ParentID != nil && *g.ParentID == id
+	})
+	if groups == nil {
+		groups = []Group{}
+	}
+	return groups, nil
+}
+
+func (r *MemoryGroupRepository) GetDescendantIDs(ctx context.Context, id int) ([]int, error) {
+	defer r.rlock()()
+	var ids []int
+	queue := []int{id}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		for _, g := range r.db.groups {
+			if g.DeletedAt == nil && g.ParentID != nil && *g.ParentID == parent && !slices.Contains(ids, g.ID) {
+				ids = append(ids, g.ID)
+				queue = append(queue, g.ID)
+			}
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
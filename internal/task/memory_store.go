@@ -0,0 +1,88 @@
+package task
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// MemoryDB keeps tasks and groups in process memory; it is shared by the
+// memory repositories the same way *sql.DB is shared by the Postgres ones.
+type MemoryDB struct {
+	mu          sync.RWMutex
+	tasks       map[int]Task
+	groups      map[int]memoryGroup
+	nextTaskID  int
+	nextGroupID int
+}
+
+type memoryGroup struct {
+	Group
+	workflow *Workflow
+}
+
+func NewMemoryDB() *MemoryDB {
+	return &MemoryDB{
+		tasks:  map[int]Task{},
+		groups: map[int]memoryGroup{},
+	}
+}
+
+// memoryConn is embedded by the memory repositories. Inside a transaction the
+// store already holds the write lock, so the repositories must not take it.
+type memoryConn struct {
+	db *MemoryDB
+	tx bool
+}
+
+func (c memoryConn) lock() func() {
+	if c.tx {
+		return func() {}
+	}
+	c.db.mu.Lock()
+	return c.db.mu.Unlock
+}
+
+func (c memoryConn) rlock() func() {
+	if c.tx {
+		return func() {}
+	}
+	c.db.mu.RLock()
+	return c.db.mu.RUnlock
+}
+
+type MemoryStore struct {
+	db *MemoryDB
+}
+
+func NewMemoryStore(db *MemoryDB) *MemoryStore {
+	return &MemoryStore{
+		db: db,
+	}
+}
+
+// WithTx runs fn under the write lock, so transactions are serialized, and
+// puts the previous state back when fn fails. Stored values are never
+// modified in place, which makes a shallow copy of the maps a full snapshot.
+func (s *MemoryStore) WithTx(ctx context.Context, fn func(repos TxRepos) error) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	tasks, groups := maps.Clone(s.db.tasks), maps.Clone(s.db.groups)
+	nextTaskID, nextGroupID := s.db.nextTaskID, s.db.nextGroupID
+	conn := memoryConn{db: s.db, tx: true}
+	if err := fn(TxRepos{Tasks: &MemoryRepository{conn}, Groups: &MemoryGroupRepository{conn}}); err != nil {
+		s.db.tasks, s.db.groups = tasks, groups
+		s.db.nextTaskID, s.db.nextGroupID = nextTaskID, nextGroupID
+		return err
+	}
+	return nil
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
@@ -0,0 +1,153 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newMemoryService() (*Service, *MemoryRepository, *MemoryGroupRepository) {
+	db := NewMemoryDB()
+	repo, groups := NewMemoryRepository(db), NewMemoryGroupRepository(db)
+	return NewService(repo, groups, WithStore(NewMemoryStore(db))), repo, groups
+}
+
+func TestMemoryStore_Rollback(t *testing.T) {
+	ctx := context.Background()
+	service, repo, groups := newMemoryService()
+	source, err := service.CreateGroup(ctx, GroupInput{Name: "Работа"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := service.CreateGroup(ctx, GroupInput{Name: "Дом"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreateTask(ctx, CreateTaskInput{Name: "Задача", GroupID: &source.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := groups.SetWorkflow(ctx, target.ID, &Workflow{
+		Initial:  "todo",
+		States:   []TaskStatus{"todo"},
+		Terminal: []TaskStatus{"todo"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.MergeGroups(ctx, target.ID, source.ID, false)
+	if !errors.Is(err, ErrInvalidStatus) {
+		t.Fatalf("ожидалась ошибка %v, получена %v", ErrInvalidStatus, err)
+	}
+	moved := 0
+	errStop := errors.New("stop")
+	err = NewMemoryStore(repo.db).WithTx(ctx, func(tx TxRepos) error {
+		if moved, err = tx.Tasks.ReassignGroup(ctx, source.ID, &target.ID); err != nil {
+			return err
+		}
+		if err := tx.Groups.Delete(ctx, source.ID); err != nil {
+			return err
+		}
+		return errStop
+	})
+	if !errors.Is(err, errStop) || moved != 1 {
+		t.Fatalf("ожидался перенос одной задачи и ошибка %v, получено %d и %v", errStop, moved, err)
+	}
+	if _, err := groups.GetById(ctx, source.ID); err != nil {
+		t.Errorf("группа должна вернуться после отката: %v", err)
+	}
+	if n, _ := repo.Count(ctx, TaskFilter{GroupID: &source.ID}); n != 1 {
+		t.Errorf("задача должна остаться в исходной группе, в группе %d задач", n)
+	}
+}
+
+func TestMemoryService_EventFailureRollsBack(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemoryDB()
+	repo := NewMemoryRepository(db)
+	events := &MockEventRepository{Err: errors.New("events unavailable")}
+	service := NewService(repo, NewMemoryGroupRepository(db), WithStore(NewMemoryStore(db)), WithEvents(events))
+
+	if _, err := service.CreateTask(ctx, CreateTaskInput{Name: "Задача"}); !errors.Is(err, events.Err) {
+		t.Fatalf("ожидалась ошибка %v, получена %v", events.Err, err)
+	}
+	if n, _ := repo.Count(ctx, TaskFilter{}); n != 0 {
+		t.Errorf("задача без события в истории не должна сохраняться, задач: %d", n)
+	}
+}
+
+func TestMemoryService_VersionAfterMoveAndRestore(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := newMemoryService()
+	first, err := service.CreateTask(ctx, CreateTaskInput{Name: "Первая"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := service.CreateTask(ctx, CreateTaskInput{Name: "Вторая"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	update := func(name string, version int) {
+		t.Helper()
+		in := UpdateTaskInput{CreateTaskInput: CreateTaskInput{Name: name}, Status: StatusNew, Version: &version}
+		if _, err := service.UpdateTask(ctx, second.ID, in); err != nil {
+			t.Fatalf("обновление с версией из ответа: %v", err)
+		}
+	}
+
+	moved, err := service.MoveTask(ctx, second.ID, nil, &first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	update("После перемещения", moved.Version)
+
+	current, err := service.GetTask(ctx, second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.DeleteTask(ctx, second.ID, &current.Version); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := service.RestoreTask(ctx, second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	update("После восстановления", restored.Version)
+}
+
+func TestMemoryService_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newMemoryService()
+	group, err := service.CreateGroup(ctx, GroupInput{Name: "Работа"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			task, err := service.CreateTask(ctx, CreateTaskInput{Name: fmt.Sprintf("Задача %d", i), GroupID: &group.ID})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := service.GetAllTasks(ctx, TaskFilter{GroupID: &group.ID}); err != nil {
+				t.Error(err)
+			}
+			if err := service.DeleteTask(ctx, task.ID, nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	trash, err := repo.GetTrash(ctx, time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash) != 20 {
+		t.Errorf("ожидалось 20 задач в корзине, получено %d", len(trash))
+	}
+}
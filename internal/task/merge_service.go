@@ -0,0 +1,154 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrInvalidMerge        = errors.New("group cannot be merged into itself or its descendants")
+	ErrSourceGroupNotFound = errors.New("source group not found")
+)
+
+// GroupMerge describes what a merge moves from the source group into the
+// target one.
+type GroupMerge struct {
+	Target   *Group  `json:"target"`
+	Source   *Group  `json:"source"`
+	Tasks    []Task  `json:"tasks"`
+	Children []Group `json:"children"`
+	DryRun   bool    `json:"dry_run"`
+}
+
+// MergeGroups moves the tasks and child groups of the source group into the
+// target and trashes the source in a single transaction. With dryRun all the
+// checks run but nothing is changed.
+func (s *Service) MergeGroups(ctx context.Context, targetId, sourceId int, dryRun bool) (*GroupMerge, error) {
+	if targetId <= 0 {
+		return nil, fmt.Errorf("incorrect id: %d", targetId)
+	}
+	if sourceId == targetId {
+		return nil, ErrInvalidMerge
+	}
+
+	merge := &GroupMerge{DryRun: dryRun}
+	err := s.atomically(ctx, func(tx *Service) error {
+		tasks, groups := tx.repo, tx.groups
+		var err error
+		if merge.Target, err = groups.GetById(ctx, targetId); err != nil {
+			return fmt.Errorf("failed to get group: %w", err)
+		}
+		if merge.Target.Archived {
+			return ErrGroupArchived
+		}
+		if merge.Source, err = groups.GetById(ctx, sourceId); err != nil {
+			if errors.Is(err, ErrGroupNotFound) {
+				return ErrSourceGroupNotFound
+			}
+			return fmt.Errorf("failed to get source group: %w", err)
+		}
+		descendants, err := groups.GetDescendantIDs(ctx, sourceId)
+		if err != nil {
+			return fmt.Errorf("failed to get child groups: %w", err)
+		}
+		if slices.Contains(descendants, targetId) {
+			return ErrInvalidMerge
+		}
+		if merge.Tasks, err = tasks.GetAll(ctx, TaskFilter{GroupID: &sourceId}); err != nil {
+			return fmt.Errorf("failed to get group tasks: %w", err)
+		}
+		if merge.Children, err = groups.GetChildren(ctx, sourceId); err != nil {
+			return fmt.Errorf("failed to get child groups: %w", err)
+		}
+		if err := checkMovedTasks(ctx, tasks, groups, &targetId, merge.Tasks); err != nil {
+			return err
+		}
+		if err := checkMovedChildren(ctx, groups, targetId, sourceId, merge.Children); err != nil {
+			return err
+		}
+		if dryRun {
+			return nil
+		}
+
+		if _, err := tasks.ReassignGroup(ctx, sourceId, &targetId); err != nil {
+			return fmt.Errorf("failed to reassign group tasks: %w", err)
+		}
+		// The source goes to the trash first, so that a child named like it
+		// can take its place under the target.
+		if err := groups.Delete(ctx, sourceId); err != nil {
+			return fmt.Errorf("failed to delete group: %w", err)
+		}
+		if err := liftChildGroups(ctx, groups, sourceId, &targetId); err != nil {
+			return err
+		}
+		for i := range merge.Tasks {
+			before := merge.Tasks[i]
+			after := before
+			after.GroupID = &targetId
+			if err := tx.record(ctx, ActionUpdate, &before, &after); err != nil {
+				return err
+			}
+			merge.Tasks[i] = after
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return merge, nil
+	}
+	for i := range merge.Children {
+		merge.Children[i].ParentID = &targetId
+	}
+	return merge, nil
+}
+
+// checkMovedTasks verifies that the target group, or no group when targetId is
+// nil, can take the tasks: their statuses must exist in its workflow and the
+// in-progress ones must fit into its WIP limit.
+func checkMovedTasks(ctx context.Context, tasks TaskRepository, groups GroupRepository, targetId *int, moved []Task) error {
+	workflow := DefaultWorkflow()
+	if targetId != nil {
+		custom, err := groups.GetWorkflow(ctx, *targetId)
+		if err != nil {
+			return fmt.Errorf("failed to get group workflow: %w", err)
+		}
+		if custom != nil {
+			workflow = custom
+		}
+	}
+	inProgress := 0
+	for _, t := range moved {
+		if !workflow.HasState(t.Status) {
+			return fmt.Errorf("%w: task %d has status %q", ErrInvalidStatus, t.ID, t.Status)
+		}
+		if t.Status == StatusInProgress {
+			inProgress++
+		}
+	}
+	return checkWIPLimit(ctx, tasks, groups, targetId, inProgress)
+}
+
+// checkMovedChildren verifies that the child groups of the source do not
+// clash on name with the children of the target, the source aside.
+func checkMovedChildren(ctx context.Context, groups GroupRepository, targetId, sourceId int, children []Group) error {
+	siblings, err := groups.GetChildren(ctx, targetId)
+	if err != nil {
+		return fmt.Errorf("failed to get child groups: %w", err)
+	}
+	taken := map[string]bool{}
+	for _, g := range siblings {
+		if g.ID != sourceId {
+			taken[groupNameKey(g.Name)] = true
+		}
+	}
+	for _, child := range children {
+		if taken[groupNameKey(child.Name)] {
+			return fmt.Errorf("%w: %q", ErrNotUniqGroup, child.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestMergeGroups(t *testing.T) {
+	sourceID, targetID := 2, 1
+	tests := []struct {
+		name          string
+		source        int
+		dryRun        bool
+		tasks         []Task
+		descendants   []int
+		children      map[int][]Group
+		workflow      *Workflow
+		expectedErr   error
+		deletedGroups []int
+		reassigned    bool
+	}{
+		{name: "Ошибка: слияние с собой", source: targetID, expectedErr: ErrInvalidMerge},
+		{name: "Ошибка: цель внутри источника", source: sourceID, descendants: []int{targetID}, expectedErr: ErrInvalidMerge},
+		{
+			name:        "Ошибка: статус не из workflow цели",
+			source:      sourceID,
+			tasks:       []Task{{ID: 7, Status: "review", GroupID: &sourceID}},
+			expectedErr: ErrInvalidStatus,
+		},
+		{
+			name:   "Ошибка: занятое имя дочерней группы",
+			source: sourceID,
+			dryRun: true,
+			children: map[int][]Group{
+				sourceID: {{ID: 3, Name: "Отчёты", ParentID: &sourceID}},
+				targetID: {{ID: 4, Name: "ОТЧЁТЫ", ParentID: &targetID}},
+			},
+			expectedErr: ErrNotUniqGroup,
+		},
+		{
+			name:   "Дочерняя группа с именем источника",
+			source: sourceID,
+			tasks:  []Task{{ID: 7, Status: StatusNew, GroupID: &sourceID}},
+			children: map[int][]Group{
+				sourceID: {{ID: 3, Name: "Работа", ParentID: &sourceID}},
+				targetID: {{ID: sourceID, Name: "Работа", ParentID: &targetID}},
+			},
+			deletedGroups: []int{sourceID},
+			reassigned:    true,
+		},
+		{
+			name:   "Пробный запуск",
+			source: sourceID,
+			dryRun: true,
+			tasks:  []Task{{ID: 7, Status: StatusNew, GroupID: &sourceID}},
+		},
+		{
+			name:          "Слияние",
+			source:        sourceID,
+			tasks:         []Task{{ID: 7, Status: StatusNew, GroupID: &sourceID}},
+			deletedGroups: []int{sourceID},
+			reassigned:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{TasksToReturn: tt.tasks}
+			mockGroupRepo := &MockGroupRepository{
+				GroupToReturn:    &Group{ID: targetID, Name: "Работа"},
+				DescendantIDs:    tt.descendants,
+				WorkflowToReturn: tt.workflow,
+				Children:         tt.children,
+			}
+			service := NewService(mockRepo, mockGroupRepo)
+			merge, err := service.MergeGroups(context.Background(), targetID, tt.source, tt.dryRun)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if !slices.Equal(mockGroupRepo.DeletedIDs, tt.deletedGroups) {
+				t.Errorf("удалены группы %v, ожидалось %v", mockGroupRepo.DeletedIDs, tt.deletedGroups)
+			}
+			if mockRepo.ReassignCalled != tt.reassigned {
+				t.Errorf("перенос задач вызван = %v, а ожидалось %v", mockRepo.ReassignCalled, tt.reassigned)
+			}
+			if err != nil {
+				return
+			}
+			if len(merge.Tasks) != len(tt.tasks) {
+				t.Errorf("ожидалось %d задач к переносу, получено %d", len(tt.tasks), len(merge.Tasks))
+			}
+			if !tt.dryRun && !sameID(merge.Tasks[0].GroupID, &targetID) {
+				t.Errorf("задача осталась в группе %v", merge.Tasks[0].GroupID)
+			}
+		})
+	}
+}
@@ -3,14 +3,16 @@ package task
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type PostgresGroupRepository struct {
-	db *sql.DB
+	db dbtx
 }
 
 func NewPostgresGroupRepository(db *sql.DB) *PostgresGroupRepository {
@@ -19,18 +21,59 @@ func NewPostgresGroupRepository(db *sql.DB) *PostgresGroupRepository {
 	}
 }
 
+const groupColumns = `id, name, slug, parent_id, description, color, icon, owner_id, archived, wip_limit, deleted_at`
+
+func scanGroup(row rowScanner, g *Group) error {
+	return row.Scan(
+		&g.ID,
+		&g.Name,
+		&g.Slug,
+		&g.ParentID,
+		&g.Description,
+		&g.Color,
+		&g.Icon,
+		&g.OwnerID,
+		&g.Archived,
+		&g.WIPLimit,
+		&g.DeletedAt,
+	)
+}
+
+func groupFKError(pgErr *pgconn.PgError) error {
+	if pgErr.ConstraintName == "fk_group_owner" {
+		return ErrUserNotFound
+	}
+	return ErrParentGroupNotFound
+}
+
 func (r *PostgresGroupRepository) Add(ctx context.Context, group *Group) error {
 	query := `
-		INSERT INTO groups (name)
-		VALUES ($1)
+		INSERT INTO groups (name, name_key, slug, parent_id, description, color, icon, owner_id, archived, wip_limit)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id
 	`
-	err := r.db.QueryRowContext(ctx, query, group.Name).Scan(&group.ID)
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		group.Name,
+		groupNameKey(group.Name),
+		group.Slug,
+		group.ParentID,
+		group.Description,
+		group.Color,
+		group.Icon,
+		group.OwnerID,
+		group.Archived,
+		group.WIPLimit,
+	).Scan(&group.ID)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
 			return fmt.Errorf("postgres.Add group: %w: ", ErrNotUniqGroup)
 		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return fmt.Errorf("postgres.Add group: %w", groupFKError(pgErr))
+		}
 		return fmt.Errorf("postgres.Add into groups: %w", err)
 	}
 	return nil
@@ -38,8 +81,8 @@ func (r *PostgresGroupRepository) Add(ctx context.Context, group *Group) error {
 
 func (r *PostgresGroupRepository) GetById(ctx context.Context, id int) (*Group, error) {
 	var group Group
-	query := `SELECT id, name FROM groups WHERE id = $1`
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&group.ID, &group.Name)
+	query := `SELECT ` + groupColumns + ` FROM groups WHERE id = $1 AND deleted_at IS NULL`
+	err := scanGroup(r.db.QueryRowContext(ctx, query, id), &group)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrGroupNotFound
@@ -49,8 +92,21 @@ func (r *PostgresGroupRepository) GetById(ctx context.Context, id int) (*Group,
 	return &group, err
 }
 
+func (r *PostgresGroupRepository) GetBySlug(ctx context.Context, slug string) (*Group, error) {
+	var group Group
+	query := `SELECT ` + groupColumns + ` FROM groups WHERE slug = $1 AND deleted_at IS NULL`
+	err := scanGroup(r.db.QueryRowContext(ctx, query, slug), &group)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrGroupNotFound
+		}
+		return nil, fmt.Errorf("postgres.GetBySlug scan group slug=%q: %w", slug, err)
+	}
+	return &group, nil
+}
+
 func (r *PostgresGroupRepository) GetAll(ctx context.Context) ([]Group, error) {
-	query := `SELECT id, name FROM groups`
+	query := `SELECT ` + groupColumns + ` FROM groups WHERE deleted_at IS NULL ORDER BY name, id`
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("postgres.GetAll query group %w", err)
@@ -60,7 +116,7 @@ func (r *PostgresGroupRepository) GetAll(ctx context.Context) ([]Group, error) {
 	var groups []Group
 	for rows.Next() {
 		var group Group
-		err := rows.Scan(&group.ID, &group.Name)
+		err := scanGroup(rows, &group)
 		if err != nil {
 			return nil, fmt.Errorf("postgres.GetAll row group %w", err)
 		}
@@ -73,13 +129,9 @@ func (r *PostgresGroupRepository) GetAll(ctx context.Context) ([]Group, error) {
 }
 
 func (r *PostgresGroupRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM groups WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	query := `UPDATE groups SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
-			return fmt.Errorf("postgres.Delete group: %w", ErrGroupHasTasks)
-		}
 		return fmt.Errorf("failed to delete group: %w", err)
 	}
 	rows, err := result.RowsAffected()
@@ -95,15 +147,33 @@ func (r *PostgresGroupRepository) Delete(ctx context.Context, id int) error {
 func (r *PostgresGroupRepository) Update(ctx context.Context, group *Group) error {
 	query := `
 		UPDATE groups
-		SET name = $1
-		WHERE id = $2
+		SET name = $1, name_key = $2, slug = $3, parent_id = $4, description = $5, color = $6, icon = $7,
+			owner_id = $8, archived = $9, wip_limit = $10
+		WHERE id = $11 AND deleted_at IS NULL
 	`
-	result, err := r.db.ExecContext(ctx, query, group.Name, group.ID)
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		group.Name,
+		groupNameKey(group.Name),
+		group.Slug,
+		group.ParentID,
+		group.Description,
+		group.Color,
+		group.Icon,
+		group.OwnerID,
+		group.Archived,
+		group.WIPLimit,
+		group.ID,
+	)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
 			return fmt.Errorf("postgres.Update group: %w", ErrNotUniqGroup)
 		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return fmt.Errorf("postgres.Update group: %w", groupFKError(pgErr))
+		}
 		return fmt.Errorf("failed to update group: %w", err)
 	}
 	rows, err := result.RowsAffected()
@@ -115,3 +185,180 @@ func (r *PostgresGroupRepository) Update(ctx context.Context, group *Group) erro
 	}
 	return nil
 }
+
+func (r *PostgresGroupRepository) GetWorkflow(ctx context.Context, groupId int) (*Workflow, error) {
+	var raw []byte
+	query := `SELECT workflow FROM groups WHERE id = $1`
+	err := r.db.QueryRowContext(ctx, query, groupId).Scan(&raw)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrGroupNotFound
+		}
+		return nil, fmt.Errorf("postgres.GetWorkflow scan group id=%d: %w", groupId, err)
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	var workflow Workflow
+	if err := json.Unmarshal(raw, &workflow); err != nil {
+		return nil, fmt.Errorf("postgres.GetWorkflow decode group id=%d: %w", groupId, err)
+	}
+	return &workflow, nil
+}
+
+func (r *PostgresGroupRepository) SetWorkflow(ctx context.Context, groupId int, workflow *Workflow) error {
+	var raw any
+	if workflow != nil {
+		data, err := json.Marshal(workflow)
+		if err != nil {
+			return fmt.Errorf("postgres.SetWorkflow encode: %w", err)
+		}
+		raw = string(data)
+	}
+	query := `UPDATE groups SET workflow = $1 WHERE id = $2 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, raw, groupId)
+	if err != nil {
+		return fmt.Errorf("failed to update group workflow: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get group rows affected %w", err)
+	}
+	if rows == 0 {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+func (r *PostgresGroupRepository) GetTrash(ctx context.Context, deletedBefore time.Time) ([]Group, error) {
+	query := `
+		SELECT ` + groupColumns + `
+		FROM groups
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		ORDER BY deleted_at, id
+	`
+	rows, err := r.db.QueryContext(ctx, query, deletedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.GetTrash query group %w", err)
+	}
+	defer rows.Close()
+
+	groups := []Group{}
+	for rows.Next() {
+		var group Group
+		if err := scanGroup(rows, &group); err != nil {
+			return nil, fmt.Errorf("postgres.GetTrash row group %w", err)
+		}
+		groups = append(groups, group)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres.GetTrash row iteration %w", err)
+	}
+	return groups, nil
+}
+
+func (r *PostgresGroupRepository) GetTrashed(ctx context.Context, id int) (*Group, error) {
+	var group Group
+	query := `SELECT ` + groupColumns + ` FROM groups WHERE id = $1 AND deleted_at IS NOT NULL`
+	err := scanGroup(r.db.QueryRowContext(ctx, query, id), &group)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrGroupNotFound
+		}
+		return nil, fmt.Errorf("postgres.GetTrashed scan group id=%d: %w", id, err)
+	}
+	return &group, nil
+}
+
+func (r *PostgresGroupRepository) Restore(ctx context.Context, id int) error {
+	query := `UPDATE groups SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("postgres.Restore group: %w", ErrNotUniqGroup)
+		}
+		return fmt.Errorf("failed to restore group: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get group rows affected %w", err)
+	}
+	if rows == 0 {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+func (r *PostgresGroupRepository) Purge(ctx context.Context, id int) error {
+	query := `DELETE FROM groups WHERE id = $1 AND deleted_at IS NOT NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return fmt.Errorf("postgres.Purge group: %w", ErrGroupHasTasks)
+		}
+		return fmt.Errorf("failed to purge group: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get group rows affected %w", err)
+	}
+	if rows == 0 {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+func (r *PostgresGroupRepository) GetChildren(ctx context.Context, id int) ([]Group, error) {
+	query := `SELECT ` + groupColumns + ` FROM groups WHERE parent_id = $1 AND deleted_at IS NULL ORDER BY name, id`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.GetChildren query group %w", err)
+	}
+	defer rows.Close()
+
+	groups := []Group{}
+	for rows.Next() {
+		var group Group
+		if err := scanGroup(rows, &group); err != nil {
+			return nil, fmt.Errorf("postgres.GetChildren row group %w", err)
+		}
+		groups = append(groups, group)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres.GetChildren row iteration %w", err)
+	}
+	return groups, nil
+}
+
+func (r *PostgresGroupRepository) GetDescendantIDs(ctx context.Context, id int) ([]int, error) {
+	query := `
+		WITH RECURSIVE descendants AS (
+			SELECT id FROM groups WHERE parent_id = $1 AND deleted_at IS NULL
+			UNION
+			SELECT g.id FROM groups g
+			JOIN descendants d ON g.parent_id = d.id
+			WHERE g.deleted_at IS NULL
+		)
+		SELECT id FROM descendants ORDER BY id
+	`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.GetDescendantIDs query group %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("postgres.GetDescendantIDs row group %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres.GetDescendantIDs row iteration %w", err)
+	}
+	return ids, nil
+}
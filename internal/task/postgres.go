@@ -6,12 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type PostgresRepository struct {
-	db *sql.DB
+	db dbtx
 }
 
 func NewPostgresRepository(db *sql.DB) *PostgresRepository {
@@ -20,11 +21,89 @@ func NewPostgresRepository(db *sql.DB) *PostgresRepository {
 	}
 }
 
+const taskColumns = `
+	t.id, t.name, t.description, t.created, t.status, t.priority, t.group_id,
+	g.name as group_name, t.start_at, t.due_at, t.parent_id, t.assignee_id,
+	t.recurrence, t.recurrence_start, t.rank, t.completed_at, t.version, t.deleted_at
+`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner, t *Task) error {
+	return row.Scan(
+		&t.ID,
+		&t.Name,
+		&t.Description,
+		&t.Created,
+		&t.Status,
+		&t.Priority,
+		&t.GroupID,
+		&t.GroupName,
+		&t.StartAt,
+		&t.DueAt,
+		&t.ParentID,
+		&t.AssigneeID,
+		&t.Recurrence,
+		&t.RecurrenceStart,
+		&t.Rank,
+		&t.CompletedAt,
+		&t.Version,
+		&t.DeletedAt,
+	)
+}
+
+func taskFKError(pgErr *pgconn.PgError) error {
+	switch pgErr.ConstraintName {
+	case "fk_task_parent":
+		return ErrParentNotFound
+	case "fk_task_assignee":
+		return ErrUserNotFound
+	}
+	return ErrGroupNotFound
+}
+
+var taskSortColumns = map[string]string{
+	"id":       "t.id",
+	"name":     "t.name",
+	"created":  "t.created",
+	"status":   "t.status",
+	"priority": "CASE t.priority WHEN 'low' THEN 0 WHEN 'normal' THEN 1 WHEN 'high' THEN 2 WHEN 'urgent' THEN 3 END",
+	"start_at": "t.start_at",
+	"due_at":   "t.due_at",
+	"rank":     "t.rank",
+}
+
+// taskOrder builds the ORDER BY clause; t.id is always appended so that pages
+// of equal keys keep a stable order.
+func taskOrder(sort []SortField) string {
+	if len(sort) == 0 {
+		sort = []SortField{{Field: "created"}}
+	}
+	terms := make([]string, 0, len(sort)+1)
+	for _, f := range sort {
+		term, ok := taskSortColumns[f.Field]
+		if !ok {
+			continue
+		}
+		if f.Desc {
+			term += " DESC"
+		}
+		terms = append(terms, term+" NULLS LAST")
+	}
+	terms = append(terms, "t.id")
+	return " ORDER BY " + strings.Join(terms, ", ")
+}
+
 func (r *PostgresRepository) Add(ctx context.Context, task *Task) error {
 	query := `
-		INSERT INTO tasks (name, description, created, status, group_id)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id
+		INSERT INTO tasks (
+			name, description, created, status, priority, group_id, start_at, due_at, parent_id, assignee_id,
+			recurrence, recurrence_start, completed_at, rank
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, (` + columnEndRank("$6", "$4") + `))
+		RETURNING id, rank, version
 	`
 	err := r.db.QueryRowContext(
 		ctx,
@@ -33,12 +112,20 @@ func (r *PostgresRepository) Add(ctx context.Context, task *Task) error {
 		task.Description,
 		task.Created,
 		task.Status,
+		task.Priority,
 		task.GroupID,
-	).Scan(&task.ID)
+		task.StartAt,
+		task.DueAt,
+		task.ParentID,
+		task.AssigneeID,
+		task.Recurrence,
+		task.RecurrenceStart,
+		task.CompletedAt,
+	).Scan(&task.ID, &task.Rank, &task.Version)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
-			return fmt.Errorf("postgres.Add: insert task: %w", ErrGroupNotFound)
+			return fmt.Errorf("postgres.Add: insert task: %w", taskFKError(pgErr))
 		}
 		return fmt.Errorf("postgres.Add: insert task: %w", err)
 	}
@@ -47,19 +134,12 @@ func (r *PostgresRepository) Add(ctx context.Context, task *Task) error {
 
 func (r *PostgresRepository) GetById(ctx context.Context, id int) (*Task, error) {
 	var t Task
-	query := `
-		SELECT id, name, description, created, status, group_id 
-		FROM tasks 
-		WHERE id = $1
+	query := `SELECT ` + taskColumns + `
+		FROM tasks t
+		LEFT JOIN groups g ON t.group_id = g.id
+		WHERE t.id = $1 AND t.deleted_at IS NULL
 	`
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&t.ID,
-		&t.Name,
-		&t.Description,
-		&t.Created,
-		&t.Status,
-		&t.GroupID,
-	)
+	err := scanTask(r.db.QueryRowContext(ctx, query, id), &t)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrTaskNotFound
@@ -69,24 +149,83 @@ func (r *PostgresRepository) GetById(ctx context.Context, id int) (*Task, error)
 	return &t, nil
 }
 
-func (r *PostgresRepository) GetAll(ctx context.Context, groupId *int) ([]Task, error) {
-	query := `
-	SELECT 
-		t.id, t.name, t.description, t.created, t.status, t.group_id,
-		g.name as group_name
-	FROM tasks t
-	JOIN LEFT groups g ON t.group_id = g.id
-	`
+// taskConditions builds the WHERE clause shared by GetAll and Count; the
+// tasks table is expected under the alias t.
+func taskConditions(filter TaskFilter) (string, []any) {
 	var args []any
-	conditions := []string{}
-	if groupId != nil {
-		args = append(args, *groupId)
-		conditions = append(conditions, fmt.Sprintf("group_id = $%d", len(args)))
+	conditions := []string{"t.deleted_at IS NULL"}
+	if filter.GroupID != nil {
+		args = append(args, *filter.GroupID)
+		conditions = append(conditions, fmt.Sprintf("t.group_id = $%d", len(args)))
 
 	}
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	if len(filter.GroupIDs) > 0 {
+		placeholders := make([]string, len(filter.GroupIDs))
+		for i, id := range filter.GroupIDs {
+			args = append(args, id)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, "t.group_id IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		conditions = append(conditions, fmt.Sprintf("t.status = $%d", len(args)))
+	}
+	if filter.ParentID != nil {
+		args = append(args, *filter.ParentID)
+		conditions = append(conditions, fmt.Sprintf("t.parent_id = $%d", len(args)))
+	}
+	if filter.AssigneeID != nil {
+		args = append(args, *filter.AssigneeID)
+		conditions = append(conditions, fmt.Sprintf("t.assignee_id = $%d", len(args)))
+	}
+	if filter.DueBefore != nil {
+		args = append(args, *filter.DueBefore)
+		conditions = append(conditions, fmt.Sprintf("t.due_at < $%d", len(args)))
+	}
+	if filter.DueAfter != nil {
+		args = append(args, *filter.DueAfter)
+		conditions = append(conditions, fmt.Sprintf("t.due_at > $%d", len(args)))
 	}
+	if filter.Overdue {
+		args = append(args, time.Now())
+		conditions = append(conditions, fmt.Sprintf("t.due_at < $%d AND t.completed_at IS NULL", len(args)))
+	}
+	if len(filter.Tags) > 0 {
+		placeholders := make([]string, len(filter.Tags))
+		for i, tag := range filter.Tags {
+			args = append(args, tag)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		tagged := `
+			SELECT COUNT(DISTINCT tg.name)
+			FROM task_tags tt
+			JOIN tags tg ON tg.id = tt.tag_id
+			WHERE tt.task_id = t.id AND tg.name IN (` + strings.Join(placeholders, ", ") + `)`
+		if filter.AllTags {
+			conditions = append(conditions, fmt.Sprintf("(%s) = %d", tagged, len(filter.Tags)))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(%s) > 0", tagged))
+		}
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *PostgresRepository) Count(ctx context.Context, filter TaskFilter) (int, error) {
+	where, args := taskConditions(filter)
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM tasks t`+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("postgres.Count: count tasks: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresRepository) GetAll(ctx context.Context, filter TaskFilter) ([]Task, error) {
+	where, args := taskConditions(filter)
+	query := `SELECT ` + taskColumns + `
+	FROM tasks t
+	LEFT JOIN groups g ON t.group_id = g.id
+	` + where + taskOrder(filter.Sort)
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("postgres.GetAll: query tasks: %w", err)
@@ -96,7 +235,7 @@ func (r *PostgresRepository) GetAll(ctx context.Context, groupId *int) ([]Task,
 	tasks := []Task{}
 	for rows.Next() {
 		var t Task
-		err := rows.Scan(&t.ID, &t.Name, &t.Description, &t.Created, &t.Status, &t.GroupID, &t.GroupName)
+		err := scanTask(rows, &t)
 		if err != nil {
 			return nil, fmt.Errorf("postgres.GetAll: scan task row: %w", err)
 		}
@@ -111,43 +250,71 @@ func (r *PostgresRepository) GetAll(ctx context.Context, groupId *int) ([]Task,
 func (r *PostgresRepository) Update(ctx context.Context, task *Task) error {
 	query := `
 		UPDATE tasks
-		SET name = $1, description = $2, status = $3, group_id = $4 
-		WHERE id = $5
+		SET name = $1, description = $2, status = $3, priority = $4, group_id = $5, start_at = $6, due_at = $7,
+			parent_id = $8, assignee_id = $9, recurrence = $10, recurrence_start = $11, completed_at = $12,
+			version = version + 1,
+			rank = CASE
+				WHEN status = $3 AND group_id IS NOT DISTINCT FROM $5 THEN rank
+				ELSE (` + columnEndRank("$5", "$3") + `)
+			END
+		WHERE id = $13 AND deleted_at IS NULL AND version = $14
+		RETURNING rank, version
 	`
-	result, err := r.db.ExecContext(
+	err := r.db.QueryRowContext(
 		ctx,
 		query,
 		task.Name,
 		task.Description,
 		task.Status,
+		task.Priority,
 		task.GroupID,
+		task.StartAt,
+		task.DueAt,
+		task.ParentID,
+		task.AssigneeID,
+		task.Recurrence,
+		task.RecurrenceStart,
+		task.CompletedAt,
 		task.ID,
-	)
+		task.Version,
+	).Scan(&task.Rank, &task.Version)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return r.updateMissError(ctx, task.ID)
+		}
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
-			return fmt.Errorf("postgres.Update: insert task: %w", ErrGroupNotFound)
+			return fmt.Errorf("postgres.Update: insert task: %w", taskFKError(pgErr))
 		}
 		return fmt.Errorf("failed to update task: %w", err)
 	}
+	return nil
+}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+// updateMissError tells a stale version from a missing task when an update
+// matched no row.
+func (r *PostgresRepository) updateMissError(ctx context.Context, id int) error {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)`
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
+		return fmt.Errorf("postgres: check task id=%d: %w", id, err)
 	}
-	if rows == 0 {
-		return ErrTaskNotFound
+	if exists {
+		return ErrConflict
 	}
-	return nil
+	return ErrTaskNotFound
 }
 
-func (r *PostgresRepository) Delete(ctx context.Context, id int) error {
+func (r *PostgresRepository) Delete(ctx context.Context, id, version int) error {
 	query := `
-	DELETE FROM tasks WHERE id = $1`
+	UPDATE tasks SET deleted_at = $1, version = version + 1
+	WHERE id = $2 AND deleted_at IS NULL AND version = $3`
 	result, err := r.db.ExecContext(
 		ctx,
 		query,
+		time.Now(),
 		id,
+		version,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
@@ -156,8 +323,105 @@ func (r *PostgresRepository) Delete(ctx context.Context, id int) error {
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
+	if rows == 0 {
+		return r.updateMissError(ctx, id)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetTrash(ctx context.Context, deletedBefore time.Time) ([]Task, error) {
+	query := `SELECT ` + taskColumns + `
+	FROM tasks t
+	LEFT JOIN groups g ON t.group_id = g.id
+	WHERE t.deleted_at IS NOT NULL AND t.deleted_at < $1
+	ORDER BY t.deleted_at, t.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, deletedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.GetTrash: query tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []Task{}
+	for rows.Next() {
+		var t Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, fmt.Errorf("postgres.GetTrash: scan task row: %w", err)
+		}
+		tasks = append(tasks, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres.GetTrash: rows iteration: %w", err)
+	}
+	return tasks, nil
+}
+
+func (r *PostgresRepository) GetTrashed(ctx context.Context, id int) (*Task, error) {
+	var t Task
+	query := `SELECT ` + taskColumns + `
+		FROM tasks t
+		LEFT JOIN groups g ON t.group_id = g.id
+		WHERE t.id = $1 AND t.deleted_at IS NOT NULL
+	`
+	err := scanTask(r.db.QueryRowContext(ctx, query, id), &t)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("postgres.GetTrashed: scan task id=%d: %w", id, err)
+	}
+	return &t, nil
+}
+
+func (r *PostgresRepository) Restore(ctx context.Context, id int) error {
+	query := `UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND deleted_at IS NOT NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore task: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
 	if rows == 0 {
 		return ErrTaskNotFound
 	}
 	return nil
 }
+
+func (r *PostgresRepository) Purge(ctx context.Context, id int) error {
+	query := `DELETE FROM tasks WHERE id = $1 AND deleted_at IS NOT NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return fmt.Errorf("postgres.Purge: %w", ErrTaskHasSubtasks)
+		}
+		return fmt.Errorf("failed to purge task: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ReassignGroup(ctx context.Context, fromGroupId int, toGroupId *int) (int, error) {
+	query := `UPDATE tasks SET group_id = $1, version = version + 1 WHERE group_id = $2 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, toGroupId, fromGroupId)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return 0, fmt.Errorf("postgres.ReassignGroup: %w", ErrGroupNotFound)
+		}
+		return 0, fmt.Errorf("failed to reassign tasks: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
@@ -0,0 +1,115 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type PostgresAttachmentRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAttachmentRepository(db *sql.DB) *PostgresAttachmentRepository {
+	return &PostgresAttachmentRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresAttachmentRepository) Add(ctx context.Context, a *Attachment) error {
+	query := `
+		INSERT INTO attachments (task_id, filename, content_type, size, storage_key, created)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		a.TaskID,
+		a.Filename,
+		a.ContentType,
+		a.Size,
+		a.StorageKey,
+		a.Created,
+	).Scan(&a.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return fmt.Errorf("postgres.Add attachment: %w", ErrTaskNotFound)
+		}
+		return fmt.Errorf("postgres.Add attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresAttachmentRepository) GetByTask(ctx context.Context, taskId int) ([]Attachment, error) {
+	query := `
+		SELECT id, task_id, filename, content_type, size, storage_key, created
+		FROM attachments
+		WHERE task_id = $1
+		ORDER BY created, id
+	`
+	rows, err := r.db.QueryContext(ctx, query, taskId)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.GetByTask: query attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []Attachment{}
+	for rows.Next() {
+		var a Attachment
+		err := rows.Scan(&a.ID, &a.TaskID, &a.Filename, &a.ContentType, &a.Size, &a.StorageKey, &a.Created)
+		if err != nil {
+			return nil, fmt.Errorf("postgres.GetByTask: scan attachment row: %w", err)
+		}
+		attachments = append(attachments, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres.GetByTask: rows iteration: %w", err)
+	}
+	return attachments, nil
+}
+
+func (r *PostgresAttachmentRepository) GetById(ctx context.Context, id int) (*Attachment, error) {
+	var a Attachment
+	query := `
+		SELECT id, task_id, filename, content_type, size, storage_key, created
+		FROM attachments
+		WHERE id = $1
+	`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&a.ID,
+		&a.TaskID,
+		&a.Filename,
+		&a.ContentType,
+		&a.Size,
+		&a.StorageKey,
+		&a.Created,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("postgres.GetById: scan attachment id=%d: %w", id, err)
+	}
+	return &a, nil
+}
+
+func (r *PostgresAttachmentRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM attachments WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get attachment rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrAttachmentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type PostgresCommentRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCommentRepository(db *sql.DB) *PostgresCommentRepository {
+	return &PostgresCommentRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresCommentRepository) Add(ctx context.Context, comment *Comment) error {
+	query := `
+		INSERT INTO comments (task_id, author_id, body, created)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		comment.TaskID,
+		comment.AuthorID,
+		comment.Body,
+		comment.Created,
+	).Scan(&comment.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			if pgErr.ConstraintName == "fk_comment_author" {
+				return fmt.Errorf("postgres.Add comment: %w", ErrUserNotFound)
+			}
+			return fmt.Errorf("postgres.Add comment: %w", ErrTaskNotFound)
+		}
+		return fmt.Errorf("postgres.Add comment: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresCommentRepository) GetByTask(ctx context.Context, taskId int) ([]Comment, error) {
+	query := `
+		SELECT id, task_id, author_id, body, created, updated
+		FROM comments
+		WHERE task_id = $1
+		ORDER BY created, id
+	`
+	rows, err := r.db.QueryContext(ctx, query, taskId)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.GetByTask: query comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []Comment{}
+	for rows.Next() {
+		var c Comment
+		err := rows.Scan(&c.ID, &c.TaskID, &c.AuthorID, &c.Body, &c.Created, &c.Updated)
+		if err != nil {
+			return nil, fmt.Errorf("postgres.GetByTask: scan comment row: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres.GetByTask: rows iteration: %w", err)
+	}
+	return comments, nil
+}
+
+func (r *PostgresCommentRepository) GetById(ctx context.Context, id int) (*Comment, error) {
+	var c Comment
+	query := `
+		SELECT id, task_id, author_id, body, created, updated
+		FROM comments
+		WHERE id = $1
+	`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&c.ID, &c.TaskID, &c.AuthorID, &c.Body, &c.Created, &c.Updated)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("postgres.GetById: scan comment id=%d: %w", id, err)
+	}
+	return &c, nil
+}
+
+func (r *PostgresCommentRepository) Update(ctx context.Context, comment *Comment) error {
+	query := `UPDATE comments SET body = $1, updated = $2 WHERE id = $3`
+	result, err := r.db.ExecContext(ctx, query, comment.Body, comment.Updated, comment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get comment rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+func (r *PostgresCommentRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM comments WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get comment rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
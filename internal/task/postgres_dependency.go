@@ -0,0 +1,109 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type PostgresDependencyRepository struct {
+	db dbtx
+}
+
+func NewPostgresDependencyRepository(db *sql.DB) *PostgresDependencyRepository {
+	return &PostgresDependencyRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresDependencyRepository) Add(ctx context.Context, dep Dependency) error {
+	query := `
+		INSERT INTO task_dependencies (blocker_id, blocked_id)
+		VALUES ($1, $2)
+	`
+	_, err := r.db.ExecContext(ctx, query, dep.BlockerID, dep.BlockedID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return fmt.Errorf("postgres.Add dependency: %w", ErrDependencyExists)
+			case "23503":
+				return fmt.Errorf("postgres.Add dependency: %w", ErrTaskNotFound)
+			case "23514":
+				return fmt.Errorf("postgres.Add dependency: %w", ErrSelfDependency)
+			}
+		}
+		return fmt.Errorf("postgres.Add dependency: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresDependencyRepository) Delete(ctx context.Context, dep Dependency) error {
+	query := `DELETE FROM task_dependencies WHERE blocker_id = $1 AND blocked_id = $2`
+	result, err := r.db.ExecContext(ctx, query, dep.BlockerID, dep.BlockedID)
+	if err != nil {
+		return fmt.Errorf("failed to delete dependency: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get dependency rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrDependencyNotFound
+	}
+	return nil
+}
+
+func (r *PostgresDependencyRepository) GetBlockers(ctx context.Context, taskId int) ([]Task, error) {
+	query := `SELECT ` + taskColumns + `
+	FROM task_dependencies d
+	JOIN tasks t ON t.id = d.blocker_id
+	LEFT JOIN groups g ON t.group_id = g.id
+	WHERE d.blocked_id = $1 AND t.deleted_at IS NULL
+	ORDER BY t.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, taskId)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.GetBlockers: query: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []Task{}
+	for rows.Next() {
+		var t Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, fmt.Errorf("postgres.GetBlockers: scan task row: %w", err)
+		}
+		tasks = append(tasks, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres.GetBlockers: rows iteration: %w", err)
+	}
+	return tasks, nil
+}
+
+func (r *PostgresDependencyRepository) GetBlockedIDs(ctx context.Context, taskId int) ([]int, error) {
+	query := `SELECT blocked_id FROM task_dependencies WHERE blocker_id = $1`
+	rows, err := r.db.QueryContext(ctx, query, taskId)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.GetBlockedIDs: query: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("postgres.GetBlockedIDs: scan row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres.GetBlockedIDs: rows iteration: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,90 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type PostgresEventRepository struct {
+	db dbtx
+}
+
+func NewPostgresEventRepository(db *sql.DB) *PostgresEventRepository {
+	return &PostgresEventRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresEventRepository) Add(ctx context.Context, event *TaskEvent) error {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return fmt.Errorf("postgres.Add event: encode changes: %w", err)
+	}
+	query := `
+		INSERT INTO task_events (task_id, actor_id, action, changes, created)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	err = r.db.QueryRowContext(
+		ctx,
+		query,
+		event.TaskID,
+		event.ActorID,
+		event.Action,
+		string(changes),
+		event.Created,
+	).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("postgres.Add event: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresEventRepository) GetByTask(ctx context.Context, taskId int) ([]TaskEvent, error) {
+	query := `
+		SELECT id, task_id, actor_id, action, changes, created
+		FROM task_events
+		WHERE task_id = $1
+		ORDER BY created, id
+	`
+	return r.query(ctx, query, taskId)
+}
+
+func (r *PostgresEventRepository) GetSince(ctx context.Context, since time.Time, limit int) ([]TaskEvent, error) {
+	query := `
+		SELECT id, task_id, actor_id, action, changes, created
+		FROM task_events
+		WHERE created >= $1
+		ORDER BY created, id
+		LIMIT $2
+	`
+	return r.query(ctx, query, since, limit)
+}
+
+func (r *PostgresEventRepository) query(ctx context.Context, query string, args ...any) ([]TaskEvent, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: query task events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []TaskEvent{}
+	for rows.Next() {
+		var e TaskEvent
+		var changes []byte
+		if err := rows.Scan(&e.ID, &e.TaskID, &e.ActorID, &e.Action, &changes, &e.Created); err != nil {
+			return nil, fmt.Errorf("postgres: scan task event row: %w", err)
+		}
+		if err := json.Unmarshal(changes, &e.Changes); err != nil {
+			return nil, fmt.Errorf("postgres: decode task event %d: %w", e.ID, err)
+		}
+		events = append(events, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: task events rows iteration: %w", err)
+	}
+	return events, nil
+}
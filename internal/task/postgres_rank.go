@@ -0,0 +1,138 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Tasks inside a board column (group, status) are ordered by a fractional
+// rank. A move puts the task halfway between its new neighbors, so only the
+// moved row is written; the column is renumbered once the gap gets too small
+// for float precision.
+const (
+	rankStep    = 1024
+	rankEpsilon = 1e-6
+)
+
+// columnEndRank returns a subquery yielding the rank after the last task of
+// the column identified by the given group and status placeholders.
+func columnEndRank(groupParam, statusParam string) string {
+	return fmt.Sprintf(`SELECT COALESCE(MAX(c.rank), 0) + %d FROM tasks c
+		WHERE c.group_id IS NOT DISTINCT FROM %s AND c.status = %s AND c.deleted_at IS NULL`,
+		rankStep, groupParam, statusParam)
+}
+
+type taskColumn struct {
+	groupId *int
+	status  TaskStatus
+}
+
+func (r *PostgresRepository) Move(ctx context.Context, id int, afterId, beforeId *int) (float64, error) {
+	var rank float64
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var col taskColumn
+		query := `SELECT group_id, status FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+		if err := tx.QueryRowContext(ctx, query, id).Scan(&col.groupId, &col.status); err != nil {
+			if err == sql.ErrNoRows {
+				return ErrTaskNotFound
+			}
+			return fmt.Errorf("postgres.Move: get task id=%d: %w", id, err)
+		}
+
+		var ok bool
+		var err error
+		rank, ok, err = moveRank(ctx, tx, col, id, afterId, beforeId)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if err := rebalanceColumn(ctx, tx, col); err != nil {
+				return err
+			}
+			if rank, _, err = moveRank(ctx, tx, col, id, afterId, beforeId); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE tasks SET rank = $1, version = version + 1 WHERE id = $2`, rank, id); err != nil {
+			return fmt.Errorf("postgres.Move: update rank: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rank, nil
+}
+
+// moveRank computes the rank between the requested neighbors; ok is false
+// when they are too close and the column has to be rebalanced first.
+func moveRank(ctx context.Context, tx dbtx, col taskColumn, id int, afterId, beforeId *int) (float64, bool, error) {
+	var lower, upper sql.NullFloat64
+	if afterId != nil {
+		if err := neighborRank(ctx, tx, *afterId, &lower); err != nil {
+			return 0, false, err
+		}
+	}
+	if beforeId != nil {
+		if err := neighborRank(ctx, tx, *beforeId, &upper); err != nil {
+			return 0, false, err
+		}
+	}
+	// With a single neighbor the other bound is the next task in the column.
+	scope := `c.group_id IS NOT DISTINCT FROM $1 AND c.status = $2 AND c.deleted_at IS NULL AND c.id <> $3`
+	switch {
+	case afterId != nil && beforeId == nil:
+		query := `SELECT MIN(c.rank) FROM tasks c WHERE ` + scope + ` AND c.rank > $4`
+		if err := tx.QueryRowContext(ctx, query, col.groupId, col.status, id, lower.Float64).Scan(&upper); err != nil {
+			return 0, false, fmt.Errorf("postgres.Move: next rank: %w", err)
+		}
+	case beforeId != nil && afterId == nil:
+		query := `SELECT MAX(c.rank) FROM tasks c WHERE ` + scope + ` AND c.rank < $4`
+		if err := tx.QueryRowContext(ctx, query, col.groupId, col.status, id, upper.Float64).Scan(&lower); err != nil {
+			return 0, false, fmt.Errorf("postgres.Move: previous rank: %w", err)
+		}
+	}
+
+	switch {
+	case lower.Valid && upper.Valid:
+		if upper.Float64-lower.Float64 < rankEpsilon {
+			return 0, false, nil
+		}
+		return (lower.Float64 + upper.Float64) / 2, true, nil
+	case lower.Valid:
+		return lower.Float64 + rankStep, true, nil
+	case upper.Valid:
+		return upper.Float64 - rankStep, true, nil
+	}
+	return 0, true, nil
+}
+
+func neighborRank(ctx context.Context, tx dbtx, id int, rank *sql.NullFloat64) error {
+	query := `SELECT rank FROM tasks WHERE id = $1 AND deleted_at IS NULL`
+	if err := tx.QueryRowContext(ctx, query, id).Scan(rank); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrTaskNotFound
+		}
+		return fmt.Errorf("postgres.Move: get neighbor id=%d: %w", id, err)
+	}
+	return nil
+}
+
+func rebalanceColumn(ctx context.Context, tx dbtx, col taskColumn) error {
+	query := `
+		UPDATE tasks AS t
+		SET rank = r.pos * $3, version = t.version + 1
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY rank, id) AS pos
+			FROM tasks
+			WHERE group_id IS NOT DISTINCT FROM $1 AND status = $2 AND deleted_at IS NULL
+		) r
+		WHERE t.id = r.id
+	`
+	if _, err := tx.ExecContext(ctx, query, col.groupId, col.status, rankStep); err != nil {
+		return fmt.Errorf("postgres.Move: rebalance column: %w", err)
+	}
+	return nil
+}
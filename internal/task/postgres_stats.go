@@ -0,0 +1,102 @@
+package task
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetStats computes the task statistics in the database; open tasks are the
+// ones without completed_at, whatever status their workflow calls terminal.
+func (r *PostgresRepository) GetStats(ctx context.Context, filter StatsFilter) (*TaskStats, error) {
+	where, args := taskConditions(TaskFilter{GroupIDs: filter.GroupIDs})
+	args = append(args, filter.Now)
+	now := fmt.Sprintf("$%d", len(args))
+	query := `
+	SELECT t.status,
+		COUNT(*),
+		COUNT(*) FILTER (WHERE t.completed_at IS NULL),
+		COUNT(*) FILTER (WHERE t.completed_at IS NULL AND t.due_at < ` + now + `),
+		COALESCE(SUM(EXTRACT(EPOCH FROM (` + now + ` - t.created))) FILTER (WHERE t.completed_at IS NULL), 0)
+	FROM tasks t` + where + `
+	GROUP BY t.status
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.GetStats: query status counts: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &TaskStats{ByStatus: map[TaskStatus]int{}, From: filter.From, To: filter.To}
+	var openAge float64
+	for rows.Next() {
+		var (
+			status               TaskStatus
+			total, open, overdue int
+			age                  float64
+		)
+		if err := rows.Scan(&status, &total, &open, &overdue, &age); err != nil {
+			return nil, fmt.Errorf("postgres.GetStats: scan status row: %w", err)
+		}
+		stats.ByStatus[status] = total
+		stats.Total += total
+		stats.Open += open
+		stats.Overdue += overdue
+		openAge += age
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres.GetStats: status rows iteration: %w", err)
+	}
+	if stats.Open > 0 {
+		stats.AvgOpenAge = openAge / float64(stats.Open) / 3600
+	}
+
+	stats.Daily, err = r.dailyStats(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *PostgresRepository) dailyStats(ctx context.Context, filter StatsFilter) ([]DailyStats, error) {
+	where, args := taskConditions(TaskFilter{GroupIDs: filter.GroupIDs})
+	args = append(args, filter.From, filter.To)
+	from, to := fmt.Sprintf("$%d::date", len(args)-1), fmt.Sprintf("$%d::date", len(args))
+	query := `
+	WITH days AS (
+		SELECT generate_series(` + from + `, ` + to + `, INTERVAL '1 day')::date AS day
+	),
+	created AS (
+		SELECT t.created::date AS day, COUNT(*) AS n
+		FROM tasks t` + where + ` AND t.created >= ` + from + ` AND t.created < ` + to + ` + 1
+		GROUP BY 1
+	),
+	completed AS (
+		SELECT t.completed_at::date AS day, COUNT(*) AS n
+		FROM tasks t` + where + ` AND t.completed_at >= ` + from + ` AND t.completed_at < ` + to + ` + 1
+		GROUP BY 1
+	)
+	SELECT days.day, COALESCE(created.n, 0), COALESCE(completed.n, 0)
+	FROM days
+	LEFT JOIN created ON created.day = days.day
+	LEFT JOIN completed ON completed.day = days.day
+	ORDER BY days.day
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.GetStats: query daily counts: %w", err)
+	}
+	defer rows.Close()
+
+	daily := []DailyStats{}
+	for rows.Next() {
+		var d DailyStats
+		if err := rows.Scan(&d.Day, &d.Created, &d.Completed); err != nil {
+			return nil, fmt.Errorf("postgres.GetStats: scan daily row: %w", err)
+		}
+		daily = append(daily, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres.GetStats: daily rows iteration: %w", err)
+	}
+	return daily, nil
+}
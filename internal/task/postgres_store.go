@@ -0,0 +1,101 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// dbtx is implemented by both *sql.DB and *sql.Tx, so repositories can run
+// inside or outside a transaction.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type PostgresStore struct {
+	db *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{
+		db: db,
+	}
+}
+
+// maxTxAttempts bounds how many times WithTx runs a transaction that keeps
+// failing to serialize.
+const maxTxAttempts = 10
+
+// WithTx runs fn at the serializable level, so the checks fn makes before its
+// writes still hold at commit; a transaction that Postgres aborts because it
+// raced with another one is run again from the start.
+func (s *PostgresStore) WithTx(ctx context.Context, fn func(repos TxRepos) error) error {
+	for attempt := 1; ; attempt++ {
+		err := s.tryTx(ctx, fn)
+		if !isSerializationFailure(err) || attempt == maxTxAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(rand.IntN(attempt*10)+1) * time.Millisecond):
+		}
+	}
+}
+
+func (s *PostgresStore) tryTx(ctx context.Context, fn func(repos TxRepos) error) error {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return fmt.Errorf("postgres: begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = fn(TxRepos{
+		Tasks:        &PostgresRepository{db: tx},
+		Groups:       &PostgresGroupRepository{db: tx},
+		Events:       &PostgresEventRepository{db: tx},
+		Dependencies: &PostgresDependencyRepository{db: tx},
+		Tags:         &PostgresTagRepository{db: tx},
+	})
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("postgres: commit transaction: %w", err)
+	}
+	return nil
+}
+
+// isSerializationFailure reports whether err aborted a transaction that is
+// safe to retry: a serialization failure or a deadlock.
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == "40001" || pgErr.Code == "40P01")
+}
+
+// inTx runs fn in the transaction db already belongs to, or in a new one.
+func inTx(ctx context.Context, db dbtx, fn func(tx *sql.Tx) error) error {
+	if tx, ok := db.(*sql.Tx); ok {
+		return fn(tx)
+	}
+	tx, err := db.(*sql.DB).BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("postgres: begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("postgres: commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type PostgresTagRepository struct {
+	db dbtx
+}
+
+func NewPostgresTagRepository(db *sql.DB) *PostgresTagRepository {
+	return &PostgresTagRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresTagRepository) Add(ctx context.Context, tag *Tag) error {
+	query := `
+		INSERT INTO tags (name)
+		VALUES ($1)
+		RETURNING id
+	`
+	err := r.db.QueryRowContext(ctx, query, tag.Name).Scan(&tag.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("postgres.Add tag: %w", ErrNotUniqTag)
+		}
+		return fmt.Errorf("postgres.Add into tags: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresTagRepository) GetById(ctx context.Context, id int) (*Tag, error) {
+	var tag Tag
+	query := `SELECT id, name FROM tags WHERE id = $1`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&tag.ID, &tag.Name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("postgres.GetById scan tag id=%d: %w", id, err)
+	}
+	return &tag, nil
+}
+
+func (r *PostgresTagRepository) GetAll(ctx context.Context) ([]Tag, error) {
+	query := `SELECT id, name FROM tags ORDER BY name`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.GetAll query tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []Tag{}
+	for rows.Next() {
+		var tag Tag
+		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+			return nil, fmt.Errorf("postgres.GetAll row tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres.GetAll tags row iteration: %w", err)
+	}
+	return tags, nil
+}
+
+func (r *PostgresTagRepository) Update(ctx context.Context, tag *Tag) error {
+	query := `UPDATE tags SET name = $1 WHERE id = $2`
+	result, err := r.db.ExecContext(ctx, query, tag.Name, tag.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("postgres.Update tag: %w", ErrNotUniqTag)
+		}
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get tag rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+func (r *PostgresTagRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM tags WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get tag rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+func (r *PostgresTagRepository) Assign(ctx context.Context, taskId, tagId int) error {
+	query := `
+		INSERT INTO task_tags (task_id, tag_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, taskId, tagId)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			if pgErr.ConstraintName == "fk_task_tags_tag" {
+				return fmt.Errorf("postgres.Assign tag: %w", ErrTagNotFound)
+			}
+			return fmt.Errorf("postgres.Assign tag: %w", ErrTaskNotFound)
+		}
+		return fmt.Errorf("postgres.Assign tag: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresTagRepository) Unassign(ctx context.Context, taskId, tagId int) error {
+	query := `DELETE FROM task_tags WHERE task_id = $1 AND tag_id = $2`
+	_, err := r.db.ExecContext(ctx, query, taskId, tagId)
+	if err != nil {
+		return fmt.Errorf("postgres.Unassign tag: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresTagRepository) GetByTasks(ctx context.Context, taskIds []int) (map[int][]Tag, error) {
+	result := make(map[int][]Tag, len(taskIds))
+	if len(taskIds) == 0 {
+		return result, nil
+	}
+	args := make([]any, len(taskIds))
+	placeholders := make([]string, len(taskIds))
+	for i, id := range taskIds {
+		args[i] = id
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	query := `
+		SELECT tt.task_id, tg.id, tg.name
+		FROM task_tags tt
+		JOIN tags tg ON tg.id = tt.tag_id
+		WHERE tt.task_id IN (` + strings.Join(placeholders, ", ") + `)
+		ORDER BY tg.name
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.GetByTasks query tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskId int
+		var tag Tag
+		if err := rows.Scan(&taskId, &tag.ID, &tag.Name); err != nil {
+			return nil, fmt.Errorf("postgres.GetByTasks row tag: %w", err)
+		}
+		result[taskId] = append(result[taskId], tag)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres.GetByTasks row iteration: %w", err)
+	}
+	return result, nil
+}
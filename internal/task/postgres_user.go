@@ -0,0 +1,108 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type PostgresUserRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresUserRepository(db *sql.DB) *PostgresUserRepository {
+	return &PostgresUserRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresUserRepository) Add(ctx context.Context, user *User) error {
+	query := `
+		INSERT INTO users (name, email)
+		VALUES ($1, $2)
+		RETURNING id
+	`
+	err := r.db.QueryRowContext(ctx, query, user.Name, user.Email).Scan(&user.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("postgres.Add user: %w", ErrNotUniqUser)
+		}
+		return fmt.Errorf("postgres.Add into users: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) GetById(ctx context.Context, id int) (*User, error) {
+	var user User
+	query := `SELECT id, name, email FROM users WHERE id = $1`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&user.ID, &user.Name, &user.Email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("postgres.GetById scan user id=%d: %w", id, err)
+	}
+	return &user, nil
+}
+
+func (r *PostgresUserRepository) GetAll(ctx context.Context) ([]User, error) {
+	query := `SELECT id, name, email FROM users ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.GetAll query users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email); err != nil {
+			return nil, fmt.Errorf("postgres.GetAll row user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres.GetAll users row iteration: %w", err)
+	}
+	return users, nil
+}
+
+func (r *PostgresUserRepository) Update(ctx context.Context, user *User) error {
+	query := `UPDATE users SET name = $1, email = $2 WHERE id = $3`
+	result, err := r.db.ExecContext(ctx, query, user.Name, user.Email, user.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("postgres.Update user: %w", ErrNotUniqUser)
+		}
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get user rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM users WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get user rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
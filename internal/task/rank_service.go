@@ -0,0 +1,68 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidMove      = errors.New("invalid move")
+	ErrNeighborNotFound = errors.New("neighbor task not found")
+)
+
+// MoveTask places a task of a board column right after the task afterId
+// and/or right before the task beforeId; both neighbors have to be in the
+// same group and status as the moved task.
+func (s *Service) MoveTask(ctx context.Context, id int, afterId, beforeId *int) (*Task, error) {
+	if afterId == nil && beforeId == nil {
+		return nil, fmt.Errorf("%w: before or after is required", ErrInvalidMove)
+	}
+	var task *Task
+	err := s.atomically(ctx, func(tx *Service) error {
+		var err error
+		if task, err = tx.GetTask(ctx, id); err != nil {
+			return err
+		}
+		var after, before *Task
+		if afterId != nil {
+			if after, err = tx.moveNeighbor(ctx, task, *afterId); err != nil {
+				return err
+			}
+		}
+		if beforeId != nil {
+			if before, err = tx.moveNeighbor(ctx, task, *beforeId); err != nil {
+				return err
+			}
+		}
+		if after != nil && before != nil && after.Rank >= before.Rank {
+			return fmt.Errorf("%w: task %d is not above task %d", ErrInvalidMove, after.ID, before.ID)
+		}
+		if _, err = tx.repo.Move(ctx, id, afterId, beforeId); err != nil {
+			return fmt.Errorf("failed to move task: %w", err)
+		}
+		task, err = tx.GetTask(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (s *Service) moveNeighbor(ctx context.Context, task *Task, id int) (*Task, error) {
+	if id == task.ID {
+		return nil, fmt.Errorf("%w: task cannot be its own neighbor", ErrInvalidMove)
+	}
+	neighbor, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			return nil, ErrNeighborNotFound
+		}
+		return nil, fmt.Errorf("failed to get neighbor task: %w", err)
+	}
+	if !sameID(neighbor.GroupID, task.GroupID) || neighbor.Status != task.Status {
+		return nil, fmt.Errorf("%w: task %d is in another column", ErrInvalidMove, id)
+	}
+	return neighbor, nil
+}
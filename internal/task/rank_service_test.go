@@ -0,0 +1,53 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+)
+
+func TestMoveTask(t *testing.T) {
+	groupID := 1
+	otherGroupID := 2
+	tasks := map[int]*Task{
+		1: {ID: 1, GroupID: &groupID, Status: StatusNew, Rank: 1024},
+		2: {ID: 2, GroupID: &groupID, Status: StatusNew, Rank: 2048},
+		3: {ID: 3, GroupID: &groupID, Status: StatusNew, Rank: 3072},
+		4: {ID: 4, GroupID: &groupID, Status: StatusInProgress, Rank: 1024},
+		5: {ID: 5, GroupID: &otherGroupID, Status: StatusNew, Rank: 1024},
+	}
+	one, two, three, four, five, missing := 1, 2, 3, 4, 5, 42
+	tests := []struct {
+		name        string
+		id          int
+		after       *int
+		before      *int
+		expectedErr error
+	}{
+		{name: "Между соседями", id: 3, after: &one, before: &two},
+		{name: "После задачи", id: 1, after: &three},
+		{name: "Ошибка: соседи не указаны", id: 1, expectedErr: ErrInvalidMove},
+		{name: "Ошибка: другой статус", id: 1, after: &four, expectedErr: ErrInvalidMove},
+		{name: "Ошибка: другая группа", id: 1, before: &five, expectedErr: ErrInvalidMove},
+		{name: "Ошибка: сосед сам себе", id: 1, after: &one, expectedErr: ErrInvalidMove},
+		{name: "Ошибка: соседи перепутаны", id: 1, after: &three, before: &two, expectedErr: ErrInvalidMove},
+		{name: "Ошибка: сосед не найден", id: 1, after: &missing, expectedErr: ErrNeighborNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{TasksByID: maps.Clone(tasks), RankToReturn: 1536}
+			service := NewService(mockRepo, nil)
+			task, err := service.MoveTask(context.Background(), tt.id, tt.after, tt.before)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if mockRepo.MoveCalled != (tt.expectedErr == nil) {
+				t.Errorf("MoveCalled = %v, а ожидалось %v", mockRepo.MoveCalled, tt.expectedErr == nil)
+			}
+			if err == nil && task.Rank != 1536 {
+				t.Errorf("ожидался ранг 1536, получен %v", task.Rank)
+			}
+		})
+	}
+}
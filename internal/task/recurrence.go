@@ -0,0 +1,158 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+const (
+	maxOccurrences = 100
+	// maxOccurrenceRange bounds the preview window, so a rule that rarely
+	// matches cannot make it walk years of candidates.
+	maxOccurrenceRange = 366 * 24 * time.Hour
+	// maxRecurrenceCount bounds COUNT: a counted series is always walked from
+	// its start.
+	maxRecurrenceCount = 10000
+)
+
+var (
+	ErrInvalidRecurrence      = errors.New("invalid recurrence rule")
+	ErrRecurrenceNeedsDue     = errors.New("recurring task must have a due date")
+	ErrTaskNotRecurring       = errors.New("task is not recurring")
+	ErrInvalidOccurrenceRange = errors.New("invalid occurrence range")
+)
+
+type Occurrence struct {
+	StartAt *time.Time `json:"start_at"`
+	DueAt   time.Time  `json:"due_at"`
+}
+
+// parseRecurrence parses an RFC 5545 RRULE value (with or without the
+// "RRULE:" prefix); DTSTART always comes from the task, not from the rule.
+func parseRecurrence(rule string, dtstart time.Time) (*rrule.RRule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" || strings.Contains(strings.ToUpper(rule), "DTSTART") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRecurrence, rule)
+	}
+	opt, err := rrule.StrToROption(rule)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	if opt.Freq > rrule.HOURLY {
+		return nil, fmt.Errorf("%w: frequency below hourly is not supported", ErrInvalidRecurrence)
+	}
+	if opt.Count > maxRecurrenceCount {
+		return nil, fmt.Errorf("%w: COUNT above %d is not supported", ErrInvalidRecurrence, maxRecurrenceCount)
+	}
+	opt.Dtstart = dtstart
+	r, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	return r, nil
+}
+
+// recurrenceFrom parses the rule like parseRecurrence, but moves DTSTART
+// forward by whole intervals to the last one not after from, so iterating the
+// rule does not walk the series from its beginning. The occurrences from from
+// on stay the same. Counted series keep their start.
+func recurrenceFrom(rule string, dtstart, from time.Time) (*rrule.RRule, error) {
+	r, err := parseRecurrence(rule, dtstart)
+	if err != nil || r.OrigOptions.Count > 0 || !from.After(dtstart) {
+		return r, err
+	}
+	opt := r.OrigOptions
+	pinRecurrenceDefaults(&opt, dtstart)
+	interval := max(opt.Interval, 1)
+	from = from.In(dtstart.Location())
+
+	// Monthly and yearly series restart at the first day of the period: the
+	// day of dtstart may not exist there, and the time of day is pinned.
+	shifted := func(k int) time.Time {
+		n := k * interval
+		switch opt.Freq {
+		case rrule.YEARLY:
+			return time.Date(dtstart.Year()+n, time.January, 1, 0, 0, 0, 0, dtstart.Location())
+		case rrule.MONTHLY:
+			return time.Date(dtstart.Year(), dtstart.Month()+time.Month(n), 1, 0, 0, 0, 0, dtstart.Location())
+		case rrule.WEEKLY:
+			return dtstart.AddDate(0, 0, 7*n)
+		case rrule.DAILY:
+			return dtstart.AddDate(0, 0, n)
+		}
+		return time.Unix(dtstart.Unix()+int64(n)*3600, 0).In(dtstart.Location())
+	}
+	// Seconds rather than a Duration, which overflows after 292 years.
+	hours := int((from.Unix() - dtstart.Unix()) / 3600)
+	var periods int
+	switch opt.Freq {
+	case rrule.YEARLY:
+		periods = from.Year() - dtstart.Year()
+	case rrule.MONTHLY:
+		periods = (from.Year()-dtstart.Year())*12 + int(from.Month()-dtstart.Month())
+	case rrule.WEEKLY:
+		periods = hours / (7 * 24)
+	case rrule.DAILY:
+		periods = hours / 24
+	default:
+		periods = hours
+	}
+	k := periods / interval
+	for k > 0 && shifted(k).After(from) {
+		k--
+	}
+	if k == 0 {
+		return r, nil
+	}
+	opt.Dtstart = shifted(k)
+	r, err = rrule.NewRRule(opt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	return r, nil
+}
+
+var rruleWeekdays = []rrule.Weekday{rrule.SU, rrule.MO, rrule.TU, rrule.WE, rrule.TH, rrule.FR, rrule.SA}
+
+// pinRecurrenceDefaults spells out the parts of the rule that rrule derives
+// from DTSTART, so that moving DTSTART keeps the same series.
+func pinRecurrenceDefaults(opt *rrule.ROption, dtstart time.Time) {
+	if len(opt.Byweekno) == 0 && len(opt.Byyearday) == 0 && len(opt.Bymonthday) == 0 &&
+		len(opt.Byweekday) == 0 && len(opt.Byeaster) == 0 {
+		switch opt.Freq {
+		case rrule.YEARLY:
+			if len(opt.Bymonth) == 0 {
+				opt.Bymonth = []int{int(dtstart.Month())}
+			}
+			opt.Bymonthday = []int{dtstart.Day()}
+		case rrule.MONTHLY:
+			opt.Bymonthday = []int{dtstart.Day()}
+		case rrule.WEEKLY:
+			opt.Byweekday = []rrule.Weekday{rruleWeekdays[dtstart.Weekday()]}
+		}
+	}
+	if len(opt.Byhour) == 0 && opt.Freq < rrule.HOURLY {
+		opt.Byhour = []int{dtstart.Hour()}
+	}
+	if len(opt.Byminute) == 0 && opt.Freq < rrule.MINUTELY {
+		opt.Byminute = []int{dtstart.Minute()}
+	}
+	if len(opt.Bysecond) == 0 && opt.Freq < rrule.SECONDLY {
+		opt.Bysecond = []int{dtstart.Second()}
+	}
+}
+
+// occurrenceAt builds an occurrence due at due, keeping the start-to-due
+// offset of the template task.
+func occurrenceAt(template *Task, due time.Time) Occurrence {
+	o := Occurrence{DueAt: due}
+	if template.StartAt != nil && template.DueAt != nil {
+		start := due.Add(template.StartAt.Sub(*template.DueAt))
+		o.StartAt = &start
+	}
+	return o
+}
@@ -0,0 +1,120 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (s *Service) GetOccurrences(ctx context.Context, id int, from, to time.Time) ([]Occurrence, error) {
+	if !to.After(from) || to.Sub(from) > maxOccurrenceRange {
+		return nil, ErrInvalidOccurrenceRange
+	}
+	task, err := s.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task.Recurrence == nil || task.RecurrenceStart == nil {
+		return nil, ErrTaskNotRecurring
+	}
+	rule, err := recurrenceFrom(*task.Recurrence, *task.RecurrenceStart, from)
+	if err != nil {
+		return nil, err
+	}
+	occurrences := []Occurrence{}
+	next := rule.Iterator()
+	for len(occurrences) < maxOccurrences {
+		due, ok := next()
+		if !ok || due.After(to) {
+			break
+		}
+		if !due.Before(from) {
+			occurrences = append(occurrences, occurrenceAt(task, due))
+		}
+	}
+	return occurrences, nil
+}
+
+// applyRecurrence validates the recurrence of a created or updated task and
+// anchors the series at its due date when the rule is new or changed.
+func applyRecurrence(task *Task, rule *string, previous *string) error {
+	if rule == nil {
+		task.Recurrence = nil
+		task.RecurrenceStart = nil
+		return nil
+	}
+	if task.DueAt == nil {
+		return ErrRecurrenceNeedsDue
+	}
+	start := *task.DueAt
+	if previous != nil && *previous == *rule && task.RecurrenceStart != nil {
+		start = *task.RecurrenceStart
+	}
+	if _, err := parseRecurrence(*rule, start); err != nil {
+		return err
+	}
+	task.Recurrence = rule
+	task.RecurrenceStart = &start
+	return nil
+}
+
+// spawnNextOccurrence creates the task for the first occurrence after both the
+// completed one and now, so late completion does not produce overdue copies.
+// It runs in the transaction of the update.
+func (s *Service) spawnNextOccurrence(ctx context.Context, done *Task, workflow *Workflow) (*Task, error) {
+	after := time.Now()
+	if done.DueAt != nil && done.DueAt.After(after) {
+		after = *done.DueAt
+	}
+	rule, err := recurrenceFrom(*done.Recurrence, *done.RecurrenceStart, after)
+	if err != nil {
+		return nil, err
+	}
+	due := rule.After(after, false)
+	if due.IsZero() {
+		return nil, nil
+	}
+	o := occurrenceAt(done, due)
+	next := &Task{
+		Name:            done.Name,
+		Description:     done.Description,
+		Created:         time.Now(),
+		Status:          workflow.Initial,
+		Priority:        done.Priority,
+		GroupID:         done.GroupID,
+		ParentID:        done.ParentID,
+		AssigneeID:      done.AssigneeID,
+		StartAt:         o.StartAt,
+		DueAt:           &o.DueAt,
+		Recurrence:      done.Recurrence,
+		RecurrenceStart: done.RecurrenceStart,
+	}
+	if err := s.repo.Add(ctx, next); err != nil {
+		return nil, fmt.Errorf("failed to add next occurrence: %w", err)
+	}
+	if err := s.copyTags(ctx, done, next); err != nil {
+		return nil, err
+	}
+	if err := s.record(ctx, ActionCreate, nil, next); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// copyTags copies the tags of the completed task to its next occurrence.
+func (s *Service) copyTags(ctx context.Context, done, next *Task) error {
+	if s.tags == nil {
+		return nil
+	}
+	byTask, err := s.tags.GetByTasks(ctx, []int{done.ID})
+	if err != nil {
+		return fmt.Errorf("failed to get task tags: %w", err)
+	}
+	for _, tag := range byTask[done.ID] {
+		if err := s.tags.Assign(ctx, next.ID, tag.ID); err != nil {
+			return fmt.Errorf("failed to copy task tags: %w", err)
+		}
+	}
+	next.Tags = byTask[done.ID]
+	return nil
+}
@@ -0,0 +1,122 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCreateTask_Recurrence(t *testing.T) {
+	due := time.Now().Add(24 * time.Hour)
+	valid := "FREQ=WEEKLY;BYDAY=MO"
+	invalid := "FREQ=FORTNIGHTLY"
+	tooOften := "FREQ=MINUTELY"
+	tests := []struct {
+		name        string
+		rule        *string
+		dueAt       *time.Time
+		expectedErr error
+	}{
+		{name: "Еженедельная задача", rule: &valid, dueAt: &due},
+		{name: "Ошибка: без срока", rule: &valid, expectedErr: ErrRecurrenceNeedsDue},
+		{name: "Ошибка: неверное правило", rule: &invalid, dueAt: &due, expectedErr: ErrInvalidRecurrence},
+		{name: "Ошибка: слишком часто", rule: &tooOften, dueAt: &due, expectedErr: ErrInvalidRecurrence},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{}
+			service := NewService(mockRepo, nil)
+			task, err := service.CreateTask(context.Background(), CreateTaskInput{
+				Name:       "Отчёт",
+				DueAt:      tt.dueAt,
+				Recurrence: tt.rule,
+			})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if err == nil && (task.RecurrenceStart == nil || !task.RecurrenceStart.Equal(due)) {
+				t.Errorf("ожидалось начало серии %v, получено %v", due, task.RecurrenceStart)
+			}
+		})
+	}
+}
+
+func TestUpdateTask_SpawnsNextOccurrence(t *testing.T) {
+	rule := "FREQ=WEEKLY"
+	due := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	start := due.Add(-2 * time.Hour)
+	mockRepo := &MockRepository{
+		TaskToReturn: &Task{
+			ID:              1,
+			Name:            "Отчёт",
+			Status:          StatusInProgress,
+			StartAt:         &start,
+			DueAt:           &due,
+			Recurrence:      &rule,
+			RecurrenceStart: &due,
+		},
+	}
+	service := NewService(mockRepo, nil)
+	task, err := service.UpdateTask(context.Background(), 1, UpdateTaskInput{
+		CreateTaskInput: CreateTaskInput{Name: "Отчёт", StartAt: &start, DueAt: &due, Recurrence: &rule},
+		Status:          StatusDone,
+	})
+	if err != nil {
+		t.Fatalf("не ожидалось ошибки, получена: %v", err)
+	}
+	if !mockRepo.AddCalled || task.NextOccurrence == nil {
+		t.Fatal("следующее повторение не создано")
+	}
+	next := mockRepo.AddedTask
+	if next.Status != StatusNew || next.DueAt == nil || !next.DueAt.Equal(due.AddDate(0, 0, 7)) {
+		t.Errorf("неверное следующее повторение: статус %s, срок %v", next.Status, next.DueAt)
+	}
+	if next.StartAt == nil || !next.StartAt.Equal(start.AddDate(0, 0, 7)) {
+		t.Errorf("ожидалось начало %v, получено %v", start.AddDate(0, 0, 7), next.StartAt)
+	}
+	if next.RecurrenceStart == nil || !next.RecurrenceStart.Equal(due) {
+		t.Errorf("серия должна сохранить начало %v, получено %v", due, next.RecurrenceStart)
+	}
+}
+
+func TestGetOccurrences(t *testing.T) {
+	rule := "FREQ=DAILY;COUNT=5"
+	seriesStart := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	mockRepo := &MockRepository{
+		TaskToReturn: &Task{ID: 1, DueAt: &seriesStart, Recurrence: &rule, RecurrenceStart: &seriesStart},
+	}
+	service := NewService(mockRepo, nil)
+	from := seriesStart.AddDate(0, 0, 1)
+	to := seriesStart.AddDate(0, 1, 0)
+	occurrences, err := service.GetOccurrences(context.Background(), 1, from, to)
+	if err != nil {
+		t.Fatalf("не ожидалось ошибки, получена: %v", err)
+	}
+	if len(occurrences) != 4 {
+		t.Fatalf("ожидалось 4 повторения, получено %d", len(occurrences))
+	}
+	if !occurrences[0].DueAt.Equal(from) || !occurrences[3].DueAt.Equal(seriesStart.AddDate(0, 0, 4)) {
+		t.Errorf("неверные даты: %v .. %v", occurrences[0].DueAt, occurrences[3].DueAt)
+	}
+}
+
+func TestGetOccurrences_FarFromStart(t *testing.T) {
+	rule := "FREQ=HOURLY"
+	seriesStart := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	mockRepo := &MockRepository{
+		TaskToReturn: &Task{ID: 1, DueAt: &seriesStart, Recurrence: &rule, RecurrenceStart: &seriesStart},
+	}
+	service := NewService(mockRepo, nil)
+	from := time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC)
+	occurrences, err := service.GetOccurrences(context.Background(), 1, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("не ожидалось ошибки, получена: %v", err)
+	}
+	if len(occurrences) != 25 || !occurrences[0].DueAt.Equal(from) {
+		t.Errorf("неверные повторения: %d, первое %v", len(occurrences), occurrences[0].DueAt)
+	}
+	if _, err := service.GetOccurrences(context.Background(), 1, from, from.AddDate(2, 0, 0)); !errors.Is(err, ErrInvalidOccurrenceRange) {
+		t.Errorf("ожидалась ошибка %v, получена %v", ErrInvalidOccurrenceRange, err)
+	}
+}
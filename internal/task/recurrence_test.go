@@ -0,0 +1,42 @@
+package task
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRecurrenceFrom_SameSeries(t *testing.T) {
+	dtstart := time.Date(2024, 1, 31, 9, 30, 0, 0, time.UTC)
+	rules := []string{
+		"FREQ=HOURLY;INTERVAL=5",
+		"FREQ=HOURLY;BYHOUR=9,17;BYDAY=MO,FR",
+		"FREQ=DAILY;INTERVAL=3",
+		"FREQ=WEEKLY;INTERVAL=2",
+		"FREQ=WEEKLY;BYDAY=TU,SA;WKST=SU",
+		"FREQ=MONTHLY",
+		"FREQ=MONTHLY;INTERVAL=5;BYDAY=-1FR",
+		"FREQ=YEARLY",
+		"FREQ=YEARLY;INTERVAL=2;BYMONTH=2;BYMONTHDAY=29",
+		"FREQ=DAILY;COUNT=400",
+	}
+	for _, rule := range rules {
+		t.Run(rule, func(t *testing.T) {
+			for _, from := range []time.Time{dtstart.AddDate(0, 0, 40), dtstart.AddDate(5, 7, 3).Add(5 * time.Hour)} {
+				to := from.AddDate(1, 0, 0)
+				full, err := parseRecurrence(rule, dtstart)
+				if err != nil {
+					t.Fatal(err)
+				}
+				shifted, err := recurrenceFrom(rule, dtstart, from)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want, got := full.Between(from, to, true), shifted.Between(from, to, true)
+				if !slices.EqualFunc(want, got, time.Time.Equal) {
+					t.Errorf("с %v: получено %d повторений, ожидалось %d", from, len(got), len(want))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,598 @@
+// Package repotest is a conformance suite for the implementations of
+// task.TaskRepository and task.GroupRepository: every backend has to pass it
+// with the same results and the same sentinel errors.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/just4fun-xd/task-manager/internal/task"
+)
+
+// Repos are the repositories under test, all over the same empty storage.
+// Store is optional; without it the transaction checks are skipped.
+type Repos struct {
+	Tasks  task.TaskRepository
+	Groups task.GroupRepository
+	Store  task.Store
+}
+
+// missingID is an id no test ever creates.
+const missingID = 1_000_000
+
+// Run runs the suite; newRepos is called once per test and must return
+// repositories over an empty storage.
+func Run(t *testing.T, newRepos func(t *testing.T) Repos) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, r Repos)
+	}{
+		{"GroupCRUD", testGroupCRUD},
+		{"GroupUnique", testGroupUnique},
+		{"GroupReferences", testGroupReferences},
+		{"GroupTree", testGroupTree},
+		{"GroupWorkflow", testGroupWorkflow},
+		{"TaskCRUD", testTaskCRUD},
+		{"TaskTrash", testTaskTrash},
+		{"TaskReferences", testTaskReferences},
+		{"TaskFilter", testTaskFilter},
+		{"TaskSort", testTaskSort},
+		{"TaskMove", testTaskMove},
+		{"ReassignGroup", testReassignGroup},
+		{"StoreRollback", testStoreRollback},
+		{"StoreConcurrent", testStoreConcurrent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepos(t))
+		})
+	}
+}
+
+// now is truncated to microseconds and kept in UTC, the precision every
+// backend keeps a timestamp with.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func expectErr(t *testing.T, what string, err, expected error) {
+	t.Helper()
+	if !errors.Is(err, expected) {
+		t.Errorf("%s: ожидалась ошибка %v, получена %v", what, expected, err)
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+}
+
+func addGroup(t *testing.T, r Repos, name, slug string, parentID *int) *task.Group {
+	t.Helper()
+	group := &task.Group{Name: name, Slug: slug, ParentID: parentID}
+	must(t, r.Groups.Add(context.Background(), group))
+	return group
+}
+
+func addTask(t *testing.T, r Repos, tk *task.Task) *task.Task {
+	t.Helper()
+	if tk.Status == "" {
+		tk.Status = task.StatusNew
+	}
+	if tk.Priority == "" {
+		tk.Priority = task.PriorityNormal
+	}
+	if tk.Created.IsZero() {
+		tk.Created = now()
+	}
+	must(t, r.Tasks.Add(context.Background(), tk))
+	return tk
+}
+
+func taskNames(tasks []task.Task) string {
+	names := make([]string, len(tasks))
+	for i, tk := range tasks {
+		names[i] = tk.Name
+	}
+	return fmt.Sprint(names)
+}
+
+func groupNames(groups []task.Group) string {
+	names := make([]string, len(groups))
+	for i, g := range groups {
+		names[i] = g.Name
+	}
+	return fmt.Sprint(names)
+}
+
+func testGroupCRUD(t *testing.T, r Repos) {
+	ctx := context.Background()
+	limit := 3
+	work := &task.Group{Name: "Работа", Slug: "rabota", Description: "офис", Color: "#ff0000", Icon: "briefcase", WIPLimit: &limit}
+	must(t, r.Groups.Add(ctx, work))
+	if work.ID == 0 {
+		t.Fatal("id группы не заполнен")
+	}
+	addGroup(t, r, "Дом", "dom", nil)
+
+	got, err := r.Groups.GetById(ctx, work.ID)
+	must(t, err)
+	if got.Name != work.Name || got.Slug != work.Slug || got.Description != work.Description ||
+		got.Color != work.Color || got.Icon != work.Icon || got.WIPLimit == nil || *got.WIPLimit != limit {
+		t.Errorf("получена группа %+v, ожидалась %+v", got, work)
+	}
+	if got, err := r.Groups.GetBySlug(ctx, "rabota"); err != nil || got.ID != work.ID {
+		t.Errorf("поиск по slug: получено %v, %v", got, err)
+	}
+	all, err := r.Groups.GetAll(ctx)
+	must(t, err)
+	if groupNames(all) != "[Дом Работа]" {
+		t.Errorf("получены группы %s, ожидались [Дом Работа]", groupNames(all))
+	}
+
+	work.Name, work.Slug, work.Archived, work.WIPLimit = "Офис", "ofis", true, nil
+	must(t, r.Groups.Update(ctx, work))
+	got, err = r.Groups.GetById(ctx, work.ID)
+	must(t, err)
+	if got.Name != "Офис" || got.Slug != "ofis" || !got.Archived || got.WIPLimit != nil {
+		t.Errorf("группа не обновилась: %+v", got)
+	}
+	if _, err := r.Groups.GetBySlug(ctx, "rabota"); !errors.Is(err, task.ErrGroupNotFound) {
+		t.Errorf("старый slug не должен находиться: %v", err)
+	}
+
+	expectErr(t, "GetById", func() error { _, err := r.Groups.GetById(ctx, missingID); return err }(), task.ErrGroupNotFound)
+	expectErr(t, "GetBySlug", func() error { _, err := r.Groups.GetBySlug(ctx, "net"); return err }(), task.ErrGroupNotFound)
+	expectErr(t, "Update", r.Groups.Update(ctx, &task.Group{ID: missingID, Name: "Нет", Slug: "net"}), task.ErrGroupNotFound)
+	expectErr(t, "Delete", r.Groups.Delete(ctx, missingID), task.ErrGroupNotFound)
+}
+
+func testGroupUnique(t *testing.T, r Repos) {
+	ctx := context.Background()
+	work := addGroup(t, r, "Work", "work", nil)
+	home := addGroup(t, r, "Home", "home", nil)
+
+	expectErr(t, "имя в другом регистре", r.Groups.Add(ctx, &task.Group{Name: "WORK", Slug: "work-2"}), task.ErrNotUniqGroup)
+	expectErr(t, "занятый slug", r.Groups.Add(ctx, &task.Group{Name: "Job", Slug: "work"}), task.ErrNotUniqGroup)
+	addGroup(t, r, "Work", "work-3", &home.ID)
+
+	home.Name = "work"
+	expectErr(t, "переименование в занятое имя", r.Groups.Update(ctx, home), task.ErrNotUniqGroup)
+
+	must(t, r.Groups.Delete(ctx, work.ID))
+	addGroup(t, r, "work", "work", nil)
+	expectErr(t, "восстановление с занятым именем", r.Groups.Restore(ctx, work.ID), task.ErrNotUniqGroup)
+}
+
+func testGroupReferences(t *testing.T, r Repos) {
+	ctx := context.Background()
+	missing := missingID
+	expectErr(t, "Add без родителя", r.Groups.Add(ctx, &task.Group{Name: "Дом", Slug: "dom", ParentID: &missing}), task.ErrParentGroupNotFound)
+
+	parent := addGroup(t, r, "Работа", "rabota", nil)
+	child := addGroup(t, r, "Проект", "proekt", &parent.ID)
+	child.ParentID = &missing
+	expectErr(t, "Update без родителя", r.Groups.Update(ctx, child), task.ErrParentGroupNotFound)
+
+	tk := addTask(t, r, &task.Task{Name: "Задача", GroupID: &child.ID})
+	must(t, r.Groups.Delete(ctx, child.ID))
+	expectErr(t, "Purge группы с задачей", r.Groups.Purge(ctx, child.ID), task.ErrGroupHasTasks)
+	must(t, r.Groups.Delete(ctx, parent.ID))
+	expectErr(t, "Purge группы с подгруппой", r.Groups.Purge(ctx, parent.ID), task.ErrGroupHasTasks)
+
+	must(t, r.Tasks.Delete(ctx, tk.ID, tk.Version))
+	must(t, r.Tasks.Purge(ctx, tk.ID))
+	must(t, r.Groups.Purge(ctx, child.ID))
+	must(t, r.Groups.Purge(ctx, parent.ID))
+	expectErr(t, "GetTrashed после Purge", func() error { _, err := r.Groups.GetTrashed(ctx, parent.ID); return err }(), task.ErrGroupNotFound)
+}
+
+func testGroupTree(t *testing.T, r Repos) {
+	ctx := context.Background()
+	root := addGroup(t, r, "Корень", "koren", nil)
+	b := addGroup(t, r, "Б", "b", &root.ID)
+	a := addGroup(t, r, "А", "a", &root.ID)
+	leaf := addGroup(t, r, "Лист", "list", &b.ID)
+	gone := addGroup(t, r, "Удалена", "udalena", &a.ID)
+	must(t, r.Groups.Delete(ctx, gone.ID))
+
+	children, err := r.Groups.GetChildren(ctx, root.ID)
+	must(t, err)
+	if groupNames(children) != "[А Б]" {
+		t.Errorf("получены подгруппы %s, ожидались [А Б]", groupNames(children))
+	}
+	ids, err := r.Groups.GetDescendantIDs(ctx, root.ID)
+	must(t, err)
+	if fmt.Sprint(ids) != fmt.Sprint([]int{b.ID, a.ID, leaf.ID}) {
+		t.Errorf("получены потомки %v, ожидались %v", ids, []int{b.ID, a.ID, leaf.ID})
+	}
+	if ids, err := r.Groups.GetDescendantIDs(ctx, leaf.ID); err != nil || len(ids) != 0 {
+		t.Errorf("у листа не должно быть потомков: %v, %v", ids, err)
+	}
+}
+
+func testGroupWorkflow(t *testing.T, r Repos) {
+	ctx := context.Background()
+	group := addGroup(t, r, "Работа", "rabota", nil)
+	if workflow, err := r.Groups.GetWorkflow(ctx, group.ID); err != nil || workflow != nil {
+		t.Errorf("у новой группы нет своего процесса: %v, %v", workflow, err)
+	}
+	workflow := &task.Workflow{
+		Initial:     "todo",
+		States:      []task.TaskStatus{"todo", "done"},
+		Transitions: []task.Transition{{From: "todo", To: "done"}},
+		Terminal:    []task.TaskStatus{"done"},
+	}
+	must(t, r.Groups.SetWorkflow(ctx, group.ID, workflow))
+	got, err := r.Groups.GetWorkflow(ctx, group.ID)
+	must(t, err)
+	if fmt.Sprint(got) != fmt.Sprint(workflow) {
+		t.Errorf("получен процесс %+v, ожидался %+v", got, workflow)
+	}
+	must(t, r.Groups.SetWorkflow(ctx, group.ID, nil))
+	if got, err := r.Groups.GetWorkflow(ctx, group.ID); err != nil || got != nil {
+		t.Errorf("процесс должен сброситься: %v, %v", got, err)
+	}
+	expectErr(t, "GetWorkflow", func() error { _, err := r.Groups.GetWorkflow(ctx, missingID); return err }(), task.ErrGroupNotFound)
+	expectErr(t, "SetWorkflow", r.Groups.SetWorkflow(ctx, missingID, workflow), task.ErrGroupNotFound)
+}
+
+func testTaskCRUD(t *testing.T, r Repos) {
+	ctx := context.Background()
+	group := addGroup(t, r, "Работа", "rabota", nil)
+	start, due := now().Add(time.Hour), now().Add(48*time.Hour)
+	rule := "FREQ=DAILY"
+	tk := addTask(t, r, &task.Task{
+		Name:            "Отчёт",
+		Description:     "квартальный",
+		Priority:        task.PriorityHigh,
+		GroupID:         &group.ID,
+		StartAt:         &start,
+		DueAt:           &due,
+		Recurrence:      &rule,
+		RecurrenceStart: &due,
+	})
+	if tk.ID == 0 || tk.Rank <= 0 {
+		t.Fatalf("id и ранг задачи не заполнены: %+v", tk)
+	}
+
+	got, err := r.Tasks.GetById(ctx, tk.ID)
+	must(t, err)
+	if got.Name != tk.Name || got.Description != tk.Description || got.Status != task.StatusNew ||
+		got.Priority != task.PriorityHigh || got.Rank != tk.Rank || !got.Created.Equal(tk.Created) {
+		t.Errorf("получена задача %+v, ожидалась %+v", got, tk)
+	}
+	if got.GroupID == nil || *got.GroupID != group.ID || got.GroupName == nil || *got.GroupName != group.Name {
+		t.Errorf("получена группа %v (%v), ожидалась %d (%s)", got.GroupID, got.GroupName, group.ID, group.Name)
+	}
+	if got.StartAt == nil || !got.StartAt.Equal(start) || got.DueAt == nil || !got.DueAt.Equal(due) {
+		t.Errorf("получены даты %v - %v, ожидались %v - %v", got.StartAt, got.DueAt, start, due)
+	}
+	if got.Recurrence == nil || *got.Recurrence != rule || got.RecurrenceStart == nil || got.CompletedAt != nil || got.DeletedAt != nil {
+		t.Errorf("неверные поля задачи: %+v", got)
+	}
+
+	other := addTask(t, r, &task.Task{Name: "Другая", GroupID: &group.ID})
+	if other.Rank <= tk.Rank {
+		t.Errorf("новая задача должна встать в конец колонки: %v <= %v", other.Rank, tk.Rank)
+	}
+
+	completed := now()
+	got.Name, got.Status, got.Priority, got.DueAt, got.CompletedAt = "Годовой отчёт", task.StatusDone, task.PriorityLow, nil, &completed
+	must(t, r.Tasks.Update(ctx, got))
+	updated, err := r.Tasks.GetById(ctx, tk.ID)
+	must(t, err)
+	if updated.Name != "Годовой отчёт" || updated.Status != task.StatusDone || updated.Priority != task.PriorityLow ||
+		updated.DueAt != nil || updated.CompletedAt == nil || !updated.CompletedAt.Equal(completed) {
+		t.Errorf("задача не обновилась: %+v", updated)
+	}
+	if updated.Rank != got.Rank {
+		t.Errorf("ранг после обновления %v, репозиторий вернул %v", updated.Rank, got.Rank)
+	}
+	if tk.Version != 1 || got.Version != 2 || updated.Version != 2 {
+		t.Errorf("версии %d -> %d (в базе %d), ожидались 1 -> 2", tk.Version, got.Version, updated.Version)
+	}
+	stale := *updated
+	stale.Version = 1
+	expectErr(t, "Update устаревшей версии", r.Tasks.Update(ctx, &stale), task.ErrConflict)
+
+	expectErr(t, "GetById", func() error { _, err := r.Tasks.GetById(ctx, missingID); return err }(), task.ErrTaskNotFound)
+	expectErr(t, "Update", r.Tasks.Update(ctx, &task.Task{ID: missingID, Name: "Нет", Status: task.StatusNew, Priority: task.PriorityNormal}), task.ErrTaskNotFound)
+	expectErr(t, "Delete", r.Tasks.Delete(ctx, missingID, 1), task.ErrTaskNotFound)
+}
+
+func testTaskTrash(t *testing.T, r Repos) {
+	ctx := context.Background()
+	tk := addTask(t, r, &task.Task{Name: "Задача"})
+	expectErr(t, "Restore живой задачи", r.Tasks.Restore(ctx, tk.ID), task.ErrTaskNotFound)
+	expectErr(t, "Purge живой задачи", r.Tasks.Purge(ctx, tk.ID), task.ErrTaskNotFound)
+
+	expectErr(t, "Delete устаревшей версии", r.Tasks.Delete(ctx, tk.ID, tk.Version+1), task.ErrConflict)
+	must(t, r.Tasks.Delete(ctx, tk.ID, tk.Version))
+	expectErr(t, "повторный Delete", r.Tasks.Delete(ctx, tk.ID, tk.Version+1), task.ErrTaskNotFound)
+	expectErr(t, "GetById удалённой", func() error { _, err := r.Tasks.GetById(ctx, tk.ID); return err }(), task.ErrTaskNotFound)
+	if tasks, err := r.Tasks.GetAll(ctx, task.TaskFilter{}); err != nil || len(tasks) != 0 {
+		t.Errorf("удалённая задача не должна попадать в список: %v, %v", taskNames(tasks), err)
+	}
+	trashed, err := r.Tasks.GetTrashed(ctx, tk.ID)
+	must(t, err)
+	if trashed.DeletedAt == nil {
+		t.Error("у задачи в корзине должна быть дата удаления")
+	}
+	trash, err := r.Tasks.GetTrash(ctx, time.Now().Add(time.Minute))
+	must(t, err)
+	if taskNames(trash) != "[Задача]" {
+		t.Errorf("получена корзина %s, ожидалась [Задача]", taskNames(trash))
+	}
+	if trash, err := r.Tasks.GetTrash(ctx, time.Now().Add(-time.Hour)); err != nil || len(trash) != 0 {
+		t.Errorf("задача удалена позже границы: %v, %v", taskNames(trash), err)
+	}
+
+	must(t, r.Tasks.Restore(ctx, tk.ID))
+	restored, err := r.Tasks.GetById(ctx, tk.ID)
+	if err != nil {
+		t.Fatalf("задача должна восстановиться: %v", err)
+	}
+	if restored.Version != tk.Version+2 {
+		t.Errorf("версия после удаления и восстановления %d, ожидалась %d", restored.Version, tk.Version+2)
+	}
+	must(t, r.Tasks.Delete(ctx, tk.ID, restored.Version))
+	must(t, r.Tasks.Purge(ctx, tk.ID))
+	expectErr(t, "GetTrashed после Purge", func() error { _, err := r.Tasks.GetTrashed(ctx, tk.ID); return err }(), task.ErrTaskNotFound)
+	expectErr(t, "Restore", r.Tasks.Restore(ctx, missingID), task.ErrTaskNotFound)
+}
+
+func testTaskReferences(t *testing.T, r Repos) {
+	ctx := context.Background()
+	missing := missingID
+	expectErr(t, "Add без группы", r.Tasks.Add(ctx, &task.Task{Name: "Задача", Status: task.StatusNew, Priority: task.PriorityNormal, Created: now(), GroupID: &missing}), task.ErrGroupNotFound)
+	expectErr(t, "Add без родителя", r.Tasks.Add(ctx, &task.Task{Name: "Задача", Status: task.StatusNew, Priority: task.PriorityNormal, Created: now(), ParentID: &missing}), task.ErrParentNotFound)
+
+	parent := addTask(t, r, &task.Task{Name: "Родитель"})
+	child := addTask(t, r, &task.Task{Name: "Подзадача", ParentID: &parent.ID})
+	child.GroupID = &missing
+	expectErr(t, "Update без группы", r.Tasks.Update(ctx, child), task.ErrGroupNotFound)
+	child.GroupID, child.ParentID = nil, &missing
+	expectErr(t, "Update без родителя", r.Tasks.Update(ctx, child), task.ErrParentNotFound)
+
+	must(t, r.Tasks.Delete(ctx, parent.ID, parent.Version))
+	expectErr(t, "Purge задачи с подзадачей", r.Tasks.Purge(ctx, parent.ID), task.ErrTaskHasSubtasks)
+}
+
+func testTaskFilter(t *testing.T, r Repos) {
+	ctx := context.Background()
+	work := addGroup(t, r, "Работа", "rabota", nil)
+	home := addGroup(t, r, "Дом", "dom", nil)
+	past, future := now().Add(-time.Hour), now().Add(time.Hour)
+	completed := now()
+	parent := addTask(t, r, &task.Task{Name: "А", GroupID: &work.ID, DueAt: &past})
+	addTask(t, r, &task.Task{Name: "Б", GroupID: &work.ID, Status: task.StatusDone, DueAt: &past, CompletedAt: &completed, ParentID: &parent.ID})
+	addTask(t, r, &task.Task{Name: "В", GroupID: &home.ID, Status: task.StatusInProgress, DueAt: &future})
+	addTask(t, r, &task.Task{Name: "Г"})
+
+	inProgress := task.StatusInProgress
+	before := now()
+	tests := []struct {
+		name     string
+		filter   task.TaskFilter
+		expected string
+	}{
+		{name: "Без фильтра", expected: "[А Б В Г]"},
+		{name: "По группе", filter: task.TaskFilter{GroupID: &work.ID}, expected: "[А Б]"},
+		{name: "По нескольким группам", filter: task.TaskFilter{GroupIDs: []int{work.ID, home.ID}}, expected: "[А Б В]"},
+		{name: "По статусу", filter: task.TaskFilter{Status: &inProgress}, expected: "[В]"},
+		{name: "По родителю", filter: task.TaskFilter{ParentID: &parent.ID}, expected: "[Б]"},
+		{name: "Срок до", filter: task.TaskFilter{DueBefore: &before}, expected: "[А Б]"},
+		{name: "Срок после", filter: task.TaskFilter{DueAfter: &before}, expected: "[В]"},
+		{name: "Просроченные", filter: task.TaskFilter{Overdue: true}, expected: "[А]"},
+		{name: "Несколько условий", filter: task.TaskFilter{GroupID: &work.ID, Overdue: true}, expected: "[А]"},
+		{name: "По тегу без назначений", filter: task.TaskFilter{Tags: []string{"bug"}}, expected: "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := r.Tasks.GetAll(ctx, tt.filter)
+			must(t, err)
+			if taskNames(tasks) != tt.expected {
+				t.Errorf("получено %s, ожидалось %s", taskNames(tasks), tt.expected)
+			}
+			count, err := r.Tasks.Count(ctx, tt.filter)
+			must(t, err)
+			if count != len(tasks) {
+				t.Errorf("Count вернул %d, в списке %d задач", count, len(tasks))
+			}
+		})
+	}
+}
+
+func testTaskSort(t *testing.T, r Repos) {
+	ctx := context.Background()
+	created := now()
+	later := created.Add(time.Hour)
+	addTask(t, r, &task.Task{Name: "Б", Created: created, Priority: task.PriorityLow, DueAt: &later})
+	addTask(t, r, &task.Task{Name: "А", Created: created, Priority: task.PriorityUrgent})
+	addTask(t, r, &task.Task{Name: "В", Created: created.Add(-time.Minute), Priority: task.PriorityHigh, DueAt: &created})
+
+	tests := []struct {
+		name     string
+		sort     []task.SortField
+		expected string
+	}{
+		{name: "По умолчанию", expected: "[В Б А]"},
+		{name: "По имени", sort: []task.SortField{{Field: "name"}}, expected: "[А Б В]"},
+		{name: "По приоритету", sort: []task.SortField{{Field: "priority", Desc: true}}, expected: "[А В Б]"},
+		{name: "Пустые сроки в конце", sort: []task.SortField{{Field: "due_at", Desc: true}}, expected: "[Б В А]"},
+		{name: "По id в обратном порядке", sort: []task.SortField{{Field: "id", Desc: true}}, expected: "[В А Б]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := r.Tasks.GetAll(ctx, task.TaskFilter{Sort: tt.sort})
+			must(t, err)
+			if taskNames(tasks) != tt.expected {
+				t.Errorf("получено %s, ожидалось %s", taskNames(tasks), tt.expected)
+			}
+		})
+	}
+}
+
+func testTaskMove(t *testing.T, r Repos) {
+	ctx := context.Background()
+	group := addGroup(t, r, "Работа", "rabota", nil)
+	var ids []int
+	for _, name := range []string{"А", "Б", "В", "Г"} {
+		ids = append(ids, addTask(t, r, &task.Task{Name: name, GroupID: &group.ID}).ID)
+	}
+	byRank := task.TaskFilter{GroupID: &group.ID, Sort: []task.SortField{{Field: "rank"}}}
+	moves := []struct {
+		name     string
+		id       int
+		after    *int
+		before   *int
+		expected string
+	}{
+		{name: "В начало", id: ids[3], before: &ids[0], expected: "[Г А Б В]"},
+		{name: "Между", id: ids[2], after: &ids[3], before: &ids[0], expected: "[Г В А Б]"},
+		{name: "В конец", id: ids[3], after: &ids[1], expected: "[В А Б Г]"},
+	}
+	for _, m := range moves {
+		rank, err := r.Tasks.Move(ctx, m.id, m.after, m.before)
+		must(t, err)
+		tasks, err := r.Tasks.GetAll(ctx, byRank)
+		must(t, err)
+		if taskNames(tasks) != m.expected {
+			t.Errorf("%s: получено %s, ожидалось %s", m.name, taskNames(tasks), m.expected)
+		}
+		got, err := r.Tasks.GetById(ctx, m.id)
+		must(t, err)
+		if got.Rank != rank {
+			t.Errorf("%s: сохранён ранг %v, возвращён %v", m.name, got.Rank, rank)
+		}
+		if got.Version < 2 {
+			t.Errorf("%s: перемещение не изменило версию %d", m.name, got.Version)
+		}
+	}
+
+	// Repeated moves into the same gap end with the column renumbered.
+	for range 60 {
+		_, err := r.Tasks.Move(ctx, ids[1], &ids[2], &ids[0])
+		must(t, err)
+		_, err = r.Tasks.Move(ctx, ids[0], &ids[2], &ids[1])
+		must(t, err)
+	}
+	tasks, err := r.Tasks.GetAll(ctx, byRank)
+	must(t, err)
+	if taskNames(tasks) != "[В А Б Г]" {
+		t.Errorf("после перенумерации получено %s, ожидалось [В А Б Г]", taskNames(tasks))
+	}
+
+	missing := missingID
+	expectErr(t, "Move", func() error { _, err := r.Tasks.Move(ctx, missingID, nil, &ids[0]); return err }(), task.ErrTaskNotFound)
+	expectErr(t, "Move к несуществующей", func() error { _, err := r.Tasks.Move(ctx, ids[0], &missing, nil); return err }(), task.ErrTaskNotFound)
+}
+
+func testReassignGroup(t *testing.T, r Repos) {
+	ctx := context.Background()
+	from := addGroup(t, r, "Работа", "rabota", nil)
+	to := addGroup(t, r, "Дом", "dom", nil)
+	addTask(t, r, &task.Task{Name: "А", GroupID: &from.ID})
+	addTask(t, r, &task.Task{Name: "Б", GroupID: &from.ID})
+	gone := addTask(t, r, &task.Task{Name: "В", GroupID: &from.ID})
+	must(t, r.Tasks.Delete(ctx, gone.ID, gone.Version))
+
+	missing := missingID
+	_, err := r.Tasks.ReassignGroup(ctx, from.ID, &missing)
+	expectErr(t, "ReassignGroup в несуществующую группу", err, task.ErrGroupNotFound)
+
+	moved, err := r.Tasks.ReassignGroup(ctx, from.ID, &to.ID)
+	must(t, err)
+	if moved != 2 {
+		t.Errorf("перенесено %d задач, ожидалось 2", moved)
+	}
+	if n, err := r.Tasks.Count(ctx, task.TaskFilter{GroupID: &to.ID}); err != nil || n != 2 {
+		t.Errorf("в новой группе %d задач (%v), ожидалось 2", n, err)
+	}
+	moved, err = r.Tasks.ReassignGroup(ctx, to.ID, nil)
+	must(t, err)
+	if tasks, err := r.Tasks.GetAll(ctx, task.TaskFilter{}); err != nil || moved != 2 || tasks[0].GroupID != nil {
+		t.Errorf("задачи должны остаться без группы: перенесено %d, %v", moved, err)
+	} else if tasks[0].Version != 3 {
+		t.Errorf("версия после двух переносов %d, ожидалась 3", tasks[0].Version)
+	}
+}
+
+func testStoreRollback(t *testing.T, r Repos) {
+	if r.Store == nil {
+		t.Skip("хранилище без транзакций")
+	}
+	ctx := context.Background()
+	group := addGroup(t, r, "Работа", "rabota", nil)
+	errStop := errors.New("stop")
+	err := r.Store.WithTx(ctx, func(tx task.TxRepos) error {
+		tk := &task.Task{Name: "Задача", Status: task.StatusNew, Priority: task.PriorityNormal, Created: now(), GroupID: &group.ID}
+		if err := tx.Tasks.Add(ctx, tk); err != nil {
+			return err
+		}
+		if _, err := tx.Tasks.GetById(ctx, tk.ID); err != nil {
+			return err
+		}
+		if err := tx.Groups.Delete(ctx, group.ID); err != nil {
+			return err
+		}
+		return errStop
+	})
+	expectErr(t, "WithTx", err, errStop)
+	if _, err := r.Groups.GetById(ctx, group.ID); err != nil {
+		t.Errorf("группа должна вернуться после отката: %v", err)
+	}
+	if n, err := r.Tasks.Count(ctx, task.TaskFilter{}); err != nil || n != 0 {
+		t.Errorf("задача не должна сохраниться после отката: %d, %v", n, err)
+	}
+
+	must(t, r.Store.WithTx(ctx, func(tx task.TxRepos) error {
+		return tx.Tasks.Add(ctx, &task.Task{Name: "Задача", Status: task.StatusNew, Priority: task.PriorityNormal, Created: now()})
+	}))
+	if n, err := r.Tasks.Count(ctx, task.TaskFilter{}); err != nil || n != 1 {
+		t.Errorf("задача должна сохраниться после фиксации: %d, %v", n, err)
+	}
+}
+
+// testStoreConcurrent runs transactions that add a task only while the group
+// has fewer than limit of them, the way the WIP limit is checked: the check
+// must still hold when they commit concurrently.
+func testStoreConcurrent(t *testing.T, r Repos) {
+	if r.Store == nil {
+		t.Skip("хранилище без транзакций")
+	}
+	ctx := context.Background()
+	group := addGroup(t, r, "Работа", "rabota", nil)
+	const limit, workers = 3, 10
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- r.Store.WithTx(ctx, func(tx task.TxRepos) error {
+				n, err := tx.Tasks.Count(ctx, task.TaskFilter{GroupID: &group.ID})
+				if err != nil || n >= limit {
+					return err
+				}
+				return tx.Tasks.Add(ctx, &task.Task{Name: "Задача", Status: task.StatusInProgress, Priority: task.PriorityNormal, Created: now(), GroupID: &group.ID})
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		must(t, err)
+	}
+	if n, err := r.Tasks.Count(ctx, task.TaskFilter{GroupID: &group.ID}); err != nil || n != limit {
+		t.Errorf("в группе %d задач (%v), ожидалось не больше %d", n, err, limit)
+	}
+}
@@ -9,50 +9,178 @@ import (
 )
 
 type Service struct {
-	repo   TaskRepository
-	groups GroupRepository
+	repo         TaskRepository
+	groups       GroupRepository
+	dependencies DependencyRepository
+	tags         TagRepository
+	users        UserRepository
+	comments     CommentRepository
+	events       EventRepository
+	stats        StatsRepository
+	store        Store
+
+	attachments      AttachmentRepository
+	attachmentStore  AttachmentStore
+	attachmentLimits AttachmentLimits
+}
+
+type Option func(*Service)
+
+func WithDependencies(dependencies DependencyRepository) Option {
+	return func(s *Service) {
+		s.dependencies = dependencies
+	}
+}
+
+func WithTags(tags TagRepository) Option {
+	return func(s *Service) {
+		s.tags = tags
+	}
+}
+
+func WithUsers(users UserRepository) Option {
+	return func(s *Service) {
+		s.users = users
+	}
+}
+
+func WithComments(comments CommentRepository) Option {
+	return func(s *Service) {
+		s.comments = comments
+	}
 }
 
-func NewService(repo TaskRepository, groups GroupRepository) *Service {
-	return &Service{
+func WithAttachments(attachments AttachmentRepository, store AttachmentStore, limits AttachmentLimits) Option {
+	return func(s *Service) {
+		s.attachments = attachments
+		s.attachmentStore = store
+		s.attachmentLimits = limits
+	}
+}
+
+func WithEvents(events EventRepository) Option {
+	return func(s *Service) {
+		s.events = events
+	}
+}
+
+func WithStats(stats StatsRepository) Option {
+	return func(s *Service) {
+		s.stats = stats
+	}
+}
+
+func NewService(repo TaskRepository, groups GroupRepository, opts ...Option) *Service {
+	s := &Service{
 		repo:   repo,
 		groups: groups,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 var (
 	ErrEmptyTaskName    = errors.New("task name cannot be empty")
 	ErrTaskNotFound     = errors.New("task not found")
-	ErrNewTaskStatus    = errors.New("cannot jump from New to Done: start working first")
 	ErrInProgressDelete = errors.New("cannot delete task with InProgress status")
 	ErrDoneEdit         = errors.New("cannot edit done task")
 	ErrGroupNotFound    = errors.New("group not found")
 	ErrGroupHasTasks    = errors.New("group has tasks")
 	ErrNotUniqGroup     = errors.New("group has not unique name")
 	ErrEmptyGroupName   = errors.New("group name cannot be empty")
+	ErrDueBeforeCreated = errors.New("due date cannot be before creation date")
+	ErrDueBeforeStart   = errors.New("due date cannot be before start date")
+	ErrParentNotFound   = errors.New("parent task not found")
+	ErrInvalidParent    = errors.New("task cannot be a subtask of itself or its subtasks")
+	ErrOpenSubtasks     = errors.New("cannot close task with open subtasks")
+	ErrParentClosed     = errors.New("parent task is closed")
+	ErrTaskHasSubtasks  = errors.New("task has subtasks")
+	ErrInvalidPriority  = errors.New("invalid task priority")
+	ErrInvalidSort      = errors.New("invalid sort parameter")
+	ErrConflict         = errors.New("task was modified concurrently")
 )
 
-func (s *Service) CreateTask(ctx context.Context, name, description string, groupId *int) (*Task, error) {
-	if strings.TrimSpace(name) == "" {
+type CreateTaskInput struct {
+	Name        string
+	Description string
+	GroupID     *int
+	ParentID    *int
+	AssigneeID  *int
+	StartAt     *time.Time
+	DueAt       *time.Time
+	Recurrence  *string
+	Priority    TaskPriority
+}
+
+type UpdateTaskInput struct {
+	CreateTaskInput
+	Status TaskStatus
+	// Version, when set, must match the stored version of the task.
+	Version *int
+}
+
+func (s *Service) CreateTask(ctx context.Context, in CreateTaskInput) (*Task, error) {
+	if strings.TrimSpace(in.Name) == "" {
 		return nil, ErrEmptyTaskName
 	}
-	if groupId != nil {
-		if _, err := s.groups.GetById(ctx, *groupId); err != nil {
-			return nil, fmt.Errorf("failed to get group: %w", err)
-		}
-
+	if in.Priority == "" {
+		in.Priority = PriorityNormal
 	}
-
-	task := &Task{
-		Name:        name,
-		Description: description,
-		Created:     time.Now(),
-		Status:      StatusNew,
-		GroupID:     groupId,
+	if !in.Priority.IsValid() {
+		return nil, ErrInvalidPriority
 	}
-	err := s.repo.Add(ctx, task)
+	created := time.Now()
+	if err := validateDates(created, in.StartAt, in.DueAt); err != nil {
+		return nil, err
+	}
+
+	var task *Task
+	err := s.atomically(ctx, func(tx *Service) error {
+		if err := tx.checkGroup(ctx, in.GroupID); err != nil {
+			return err
+		}
+		if in.ParentID != nil {
+			if err := tx.checkParent(ctx, 0, *in.ParentID); err != nil {
+				return err
+			}
+		}
+		if err := tx.checkUser(ctx, in.AssigneeID); err != nil {
+			return err
+		}
+		workflow, err := tx.workflowFor(ctx, in.GroupID)
+		if err != nil {
+			return err
+		}
+		if workflow.Initial == StatusInProgress {
+			if err := checkWIPLimit(ctx, tx.repo, tx.groups, in.GroupID, 1); err != nil {
+				return err
+			}
+		}
+
+		task = &Task{
+			Name:        in.Name,
+			Description: in.Description,
+			Created:     created,
+			Status:      workflow.Initial,
+			Priority:    in.Priority,
+			GroupID:     in.GroupID,
+			ParentID:    in.ParentID,
+			AssigneeID:  in.AssigneeID,
+			StartAt:     in.StartAt,
+			DueAt:       in.DueAt,
+		}
+		if err := applyRecurrence(task, in.Recurrence, nil); err != nil {
+			return err
+		}
+		if err := tx.repo.Add(ctx, task); err != nil {
+			return fmt.Errorf("failed to add task: %w", err)
+		}
+		return tx.record(ctx, ActionCreate, nil, task)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to add task: %w", err)
+		return nil, err
 	}
 	return task, nil
 }
@@ -68,58 +196,220 @@ func (s *Service) GetTask(ctx context.Context, id int) (*Task, error) {
 	return task, nil
 }
 
-func (s *Service) GetAllTasks(ctx context.Context, groupId *int) ([]Task, error) {
-	if groupId != nil {
-		_, err := s.groups.GetById(ctx, *groupId)
+func (s *Service) GetAllTasks(ctx context.Context, filter TaskFilter) ([]Task, error) {
+	if filter.GroupSlug != "" {
+		group, err := s.GetGroupBySlug(ctx, filter.GroupSlug)
+		if err != nil {
+			return nil, fmt.Errorf("fillter validation: %w", err)
+		}
+		filter.GroupID = &group.ID
+		filter.GroupSlug = ""
+	}
+	if filter.GroupID != nil {
+		_, err := s.groups.GetById(ctx, *filter.GroupID)
 		if err != nil {
 			return nil, fmt.Errorf("fillter validation: group not found: %w", err)
 		}
+		if filter.Recursive {
+			descendants, err := s.groups.GetDescendantIDs(ctx, *filter.GroupID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get child groups: %w", err)
+			}
+			filter.GroupIDs = append([]int{*filter.GroupID}, descendants...)
+			filter.GroupID = nil
+		}
 	}
-	tasks, err := s.repo.GetAll(ctx, groupId)
+	if filter.AssigneeID != nil {
+		if err := s.checkUser(ctx, filter.AssigneeID); err != nil {
+			return nil, fmt.Errorf("fillter validation: %w", err)
+		}
+	}
+	filter.Tags = normalizeTagFilter(filter.Tags)
+	tasks, err := s.repo.GetAll(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all tasks: %w", err)
 	}
+	if err := s.attachTags(ctx, tasks); err != nil {
+		return nil, err
+	}
 	return tasks, nil
 }
 
-func (s *Service) UpdateTask(ctx context.Context, id int, name, description string, status TaskStatus, groupId *int) (*Task, error) {
+func (s *Service) UpdateTask(ctx context.Context, id int, in UpdateTaskInput) (*Task, error) {
+	var task *Task
+	err := s.atomically(ctx, func(tx *Service) error {
+		var err error
+		task, err = tx.updateTask(ctx, id, in)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// updateTask is the transactional part of UpdateTask.
+func (s *Service) updateTask(ctx context.Context, id int, in UpdateTaskInput) (*Task, error) {
 	task, err := s.GetTask(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get task for update: %w", err)
 	}
-	if strings.TrimSpace(name) == "" {
+	if in.Version != nil && *in.Version != task.Version {
+		return nil, ErrConflict
+	}
+	if strings.TrimSpace(in.Name) == "" {
 		return nil, ErrEmptyTaskName
 	}
-	if task.Status == StatusNew && status == StatusDone {
-		return nil, ErrNewTaskStatus
+	if in.Priority != "" && !in.Priority.IsValid() {
+		return nil, ErrInvalidPriority
 	}
-	if task.Status == StatusDone {
+	current, err := s.workflowFor(ctx, task.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if current.IsTerminal(task.Status) {
 		return nil, ErrDoneEdit
 	}
+	if err := validateDates(task.Created, in.StartAt, in.DueAt); err != nil {
+		return nil, err
+	}
+	workflow := current
+	if !sameID(task.GroupID, in.GroupID) {
+		if err := s.checkGroup(ctx, in.GroupID); err != nil {
+			return nil, err
+		}
+		workflow, err = s.workflowFor(ctx, in.GroupID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if in.ParentID != nil && !sameID(task.ParentID, in.ParentID) {
+		if err := s.checkParent(ctx, task.ID, *in.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	if !sameID(task.AssigneeID, in.AssigneeID) {
+		if err := s.checkUser(ctx, in.AssigneeID); err != nil {
+			return nil, err
+		}
+	}
 
-	task.Name = name
-	task.Description = description
-	task.Status = status
-	task.GroupID = groupId
+	before := *task
+	from := task.Status
+	task.Name = in.Name
+	task.Description = in.Description
+	task.Status = in.Status
+	task.GroupID = in.GroupID
+	task.StartAt = in.StartAt
+	task.DueAt = in.DueAt
+	task.ParentID = in.ParentID
+	task.AssigneeID = in.AssigneeID
+	if in.Priority != "" {
+		task.Priority = in.Priority
+	}
+	if err := applyRecurrence(task, in.Recurrence, task.Recurrence); err != nil {
+		return nil, err
+	}
+	if err := workflow.CheckTransition(task, from, in.Status); err != nil {
+		return nil, err
+	}
+	if enteringWIP(before.GroupID, from, task.GroupID, task.Status) {
+		if err := checkWIPLimit(ctx, s.repo, s.groups, task.GroupID, 1); err != nil {
+			return nil, err
+		}
+	}
+	if from == workflow.Initial && from != in.Status {
+		if err := s.checkBlockers(ctx, task.ID); err != nil {
+			return nil, err
+		}
+	}
+	_, progress, err := s.subtasks(ctx, task.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if from != in.Status && workflow.IsTerminal(in.Status) && progress != nil && progress.Done < progress.Total {
+		return nil, ErrOpenSubtasks
+	}
+	task.Progress = progress
+	if from != in.Status && workflow.IsTerminal(in.Status) {
+		completed := time.Now()
+		task.CompletedAt = &completed
+	}
 
 	err = s.repo.Update(ctx, task)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update task: %w", err)
 	}
-	return task, err
+	if err := s.record(ctx, ActionUpdate, &before, task); err != nil {
+		return nil, err
+	}
+	if from != in.Status && workflow.IsTerminal(in.Status) && task.Recurrence != nil {
+		task.NextOccurrence, err = s.spawnNextOccurrence(ctx, task, workflow)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return task, nil
 }
 
-func (s *Service) DeleteTask(ctx context.Context, id int) error {
-	task, err := s.GetTask(ctx, id)
-	if err != nil {
-		return fmt.Errorf("failed to get task for delete: %w", err)
+// DeleteTask moves the task to the trash; a non-nil version must match the
+// stored one.
+func (s *Service) DeleteTask(ctx context.Context, id int, version *int) error {
+	return s.atomically(ctx, func(tx *Service) error {
+		task, err := tx.GetTask(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get task for delete: %w", err)
+		}
+		if version != nil && *version != task.Version {
+			return ErrConflict
+		}
+		if task.Status == StatusInProgress {
+			return ErrInProgressDelete
+		}
+		children, err := tx.repo.GetAll(ctx, TaskFilter{ParentID: &id})
+		if err != nil {
+			return fmt.Errorf("failed to get subtasks: %w", err)
+		}
+		if len(children) > 0 {
+			return ErrTaskHasSubtasks
+		}
+		if err := tx.repo.Delete(ctx, id, task.Version); err != nil {
+			return fmt.Errorf("failed to delete task: %w", err)
+		}
+		return tx.record(ctx, ActionDelete, task, nil)
+	})
+}
+
+func validateDates(created time.Time, startAt, dueAt *time.Time) error {
+	if dueAt == nil {
+		return nil
 	}
-	if task.Status == StatusInProgress {
-		return ErrInProgressDelete
+	if dueAt.Before(created) {
+		return ErrDueBeforeCreated
 	}
-	err = s.repo.Delete(ctx, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete task: %w", err)
+	if startAt != nil && dueAt.Before(*startAt) {
+		return ErrDueBeforeStart
 	}
 	return nil
 }
+
+func (s *Service) workflowFor(ctx context.Context, groupId *int) (*Workflow, error) {
+	if groupId == nil {
+		return DefaultWorkflow(), nil
+	}
+	workflow, err := s.groups.GetWorkflow(ctx, *groupId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group workflow: %w", err)
+	}
+	if workflow == nil {
+		return DefaultWorkflow(), nil
+	}
+	return workflow, nil
+}
+
+func sameID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 )
 
 type MockRepository struct {
@@ -13,15 +14,32 @@ type MockRepository struct {
 	AddedTask        *Task
 	TaskToReturn     *Task
 	UpdatedTask      *Task
-	GetAllCalledWith *int
+	GetAllCalledWith *TaskFilter
 	GetAllCalled     bool
+	TasksToReturn    []Task
+	TasksByID        map[int]*Task
+	TrashToReturn    []Task
+	RestoreCalled    bool
+	PurgedIDs        []int
+	MoveCalled       bool
+	RankToReturn     float64
+	DeletedIDs       []int
+	ReassignedTo     *int
+	ReassignCalled   bool
+	CountToReturn    int
 }
 
 type MockGroupRepository struct {
-	AddCalled     bool
-	AddedGroup    *Group
-	GroupToReturn *Group
-	ErrorToReturn error
+	AddCalled        bool
+	AddedGroup       *Group
+	GroupToReturn    *Group
+	ErrorToReturn    error
+	WorkflowToReturn *Workflow
+	SavedWorkflow    *Workflow
+	GroupsToReturn   []Group
+	DescendantIDs    []int
+	DeletedIDs       []int
+	Children         map[int][]Group
 }
 
 func (m *MockRepository) Add(ctx context.Context, task *Task) error {
@@ -30,12 +48,23 @@ func (m *MockRepository) Add(ctx context.Context, task *Task) error {
 	return nil
 }
 
-func (m *MockRepository) GetAll(ctx context.Context, groupId *int) ([]Task, error) {
+func (m *MockRepository) GetAll(ctx context.Context, filter TaskFilter) ([]Task, error) {
 	m.GetAllCalled = true
-	m.GetAllCalledWith = groupId
-	return nil, nil
+	m.GetAllCalledWith = &filter
+	return m.TasksToReturn, nil
+}
+func (m *MockRepository) Count(ctx context.Context, filter TaskFilter) (int, error) {
+	return m.CountToReturn, nil
 }
 func (m *MockRepository) GetById(ctx context.Context, id int) (*Task, error) {
+	if m.TasksByID != nil {
+		t, ok := m.TasksByID[id]
+		if !ok {
+			return nil, ErrTaskNotFound
+		}
+		copied := *t
+		return &copied, nil
+	}
 	return m.TaskToReturn, nil
 }
 func (m *MockRepository) Update(ctx context.Context, task *Task) error {
@@ -43,24 +72,100 @@ func (m *MockRepository) Update(ctx context.Context, task *Task) error {
 	m.UpdateCalled = true
 	return nil
 }
-func (m *MockRepository) Delete(ctx context.Context, id int) error { return nil }
+func (m *MockRepository) Delete(ctx context.Context, id, version int) error {
+	m.DeletedIDs = append(m.DeletedIDs, id)
+	return nil
+}
+func (m *MockRepository) GetTrash(ctx context.Context, deletedBefore time.Time) ([]Task, error) {
+	return m.TrashToReturn, nil
+}
+func (m *MockRepository) GetTrashed(ctx context.Context, id int) (*Task, error) {
+	for _, t := range m.TrashToReturn {
+		if t.ID == id {
+			return &t, nil
+		}
+	}
+	return nil, ErrTaskNotFound
+}
+func (m *MockRepository) Restore(ctx context.Context, id int) error {
+	m.RestoreCalled = true
+	for _, t := range m.TrashToReturn {
+		if t.ID == id && m.TasksByID != nil {
+			t.DeletedAt = nil
+			m.TasksByID[id] = &t
+		}
+	}
+	return nil
+}
+func (m *MockRepository) Purge(ctx context.Context, id int) error {
+	m.PurgedIDs = append(m.PurgedIDs, id)
+	return nil
+}
+func (m *MockRepository) ReassignGroup(ctx context.Context, fromGroupId int, toGroupId *int) (int, error) {
+	m.ReassignCalled = true
+	m.ReassignedTo = toGroupId
+	return len(m.TasksToReturn), nil
+}
+func (m *MockRepository) Move(ctx context.Context, id int, afterId, beforeId *int) (float64, error) {
+	m.MoveCalled = true
+	if t, ok := m.TasksByID[id]; ok {
+		moved := *t
+		moved.Rank = m.RankToReturn
+		m.TasksByID[id] = &moved
+	}
+	return m.RankToReturn, nil
+}
 
 func (m *MockGroupRepository) Add(ctx context.Context, group *Group) error {
 	m.AddCalled = true
 	m.AddedGroup = group
 	return m.ErrorToReturn
 }
-func (m *MockGroupRepository) GetAll(ctx context.Context) ([]Group, error) { return nil, nil }
+func (m *MockGroupRepository) GetAll(ctx context.Context) ([]Group, error) {
+	return m.GroupsToReturn, nil
+}
 func (m *MockGroupRepository) GetById(ctx context.Context, id int) (*Group, error) {
 	return m.GroupToReturn, m.ErrorToReturn
 }
+func (m *MockGroupRepository) GetBySlug(ctx context.Context, slug string) (*Group, error) {
+	for _, g := range m.GroupsToReturn {
+		if g.Slug == slug {
+			return &g, nil
+		}
+	}
+	return nil, ErrGroupNotFound
+}
 func (m *MockGroupRepository) Update(ctx context.Context, group *Group) error { return nil }
-func (m *MockGroupRepository) Delete(ctx context.Context, id int) error       { return nil }
+func (m *MockGroupRepository) Delete(ctx context.Context, id int) error {
+	m.DeletedIDs = append(m.DeletedIDs, id)
+	return nil
+}
+func (m *MockGroupRepository) GetWorkflow(ctx context.Context, groupId int) (*Workflow, error) {
+	return m.WorkflowToReturn, m.ErrorToReturn
+}
+func (m *MockGroupRepository) SetWorkflow(ctx context.Context, groupId int, workflow *Workflow) error {
+	m.SavedWorkflow = workflow
+	return m.ErrorToReturn
+}
+func (m *MockGroupRepository) GetTrash(ctx context.Context, deletedBefore time.Time) ([]Group, error) {
+	return nil, nil
+}
+func (m *MockGroupRepository) GetTrashed(ctx context.Context, id int) (*Group, error) {
+	return m.GroupToReturn, m.ErrorToReturn
+}
+func (m *MockGroupRepository) Restore(ctx context.Context, id int) error { return m.ErrorToReturn }
+func (m *MockGroupRepository) Purge(ctx context.Context, id int) error   { return m.ErrorToReturn }
+func (m *MockGroupRepository) GetChildren(ctx context.Context, id int) ([]Group, error) {
+	return m.Children[id], nil
+}
+func (m *MockGroupRepository) GetDescendantIDs(ctx context.Context, id int) ([]int, error) {
+	return m.DescendantIDs, nil
+}
 
 func TestCreateTask_EmptyName(t *testing.T) {
 	mockRepo := &MockRepository{}
 	service := NewService(mockRepo, nil)
-	_, err := service.CreateTask(context.Background(), "", "Описание", nil)
+	_, err := service.CreateTask(context.Background(), CreateTaskInput{Name: "", Description: "Описание"})
 
 	if !errors.Is(err, ErrEmptyTaskName) {
 		t.Errorf("ожидалась ошибка %v, получена %v", ErrEmptyTaskName, err)
@@ -78,7 +183,7 @@ func TestCreateGroup_DuplicateName(t *testing.T) {
 	}
 	service := NewService(mockRepo, mockGroupRepo)
 	groupName := "DuplicateGroupName"
-	_, err := service.CreateGroup(context.Background(), groupName)
+	_, err := service.CreateGroup(context.Background(), GroupInput{Name: groupName})
 	if !errors.Is(err, ErrNotUniqGroup) {
 		t.Errorf("ожидалась ошибка %v, получена %v", ErrNotUniqGroup, err)
 	}
@@ -100,7 +205,7 @@ func TestCreateGroup_Success(t *testing.T) {
 
 	groupName := "Работа"
 
-	_, err := service.CreateGroup(context.Background(), groupName)
+	_, err := service.CreateGroup(context.Background(), GroupInput{Name: groupName})
 	if err != nil {
 		t.Errorf("не ожидалось ошибки, получена: %v", err)
 	}
@@ -124,7 +229,7 @@ func TestCreateTaskWithGroup(t *testing.T) {
 	desc := "Бородинский"
 	groupID := 1
 	service := NewService(mockRepo, mockGroupRepo)
-	task, err := service.CreateTask(context.Background(), name, desc, &groupID)
+	task, err := service.CreateTask(context.Background(), CreateTaskInput{Name: name, Description: desc, GroupID: &groupID})
 	if err != nil {
 		t.Fatalf("ожидалось error = nil, получено: %v", err)
 	}
@@ -161,7 +266,7 @@ func TestGetAllTasks_GroupNotFound(t *testing.T) {
 	}
 	service := NewService(mockRepo, mockGroupRepo)
 	id := 10
-	tasks, err := service.GetAllTasks(context.Background(), &id)
+	tasks, err := service.GetAllTasks(context.Background(), TaskFilter{GroupID: &id})
 	if !errors.Is(err, mockGroupRepo.ErrorToReturn) {
 		t.Errorf("ожидалось error = %v, получена %v", mockGroupRepo.ErrorToReturn, err)
 	}
@@ -179,12 +284,19 @@ func TestGetAllTasks_Filtering(t *testing.T) {
 	mockGroupRepo := &MockGroupRepository{}
 	service := NewService(mockRepo, mockGroupRepo)
 	groupId := 5
-	_, _ = service.GetAllTasks(context.Background(), &groupId)
-	if mockRepo.GetAllCalledWith == nil {
-		t.Error("ожидалось groupId != nil ")
+	dueBefore := time.Now()
+	_, _ = service.GetAllTasks(context.Background(), TaskFilter{GroupID: &groupId, DueBefore: &dueBefore, Overdue: true})
+	if mockRepo.GetAllCalledWith == nil || mockRepo.GetAllCalledWith.GroupID == nil {
+		t.Fatal("ожидалось groupId != nil ")
+	}
+	if *mockRepo.GetAllCalledWith.GroupID != groupId {
+		t.Errorf("ожидалось groupId = %d, получена %d", groupId, *mockRepo.GetAllCalledWith.GroupID)
 	}
-	if *mockRepo.GetAllCalledWith != groupId {
-		t.Errorf("ожидалось groupId = %d, получена %d", groupId, *mockRepo.GetAllCalledWith)
+	if mockRepo.GetAllCalledWith.DueBefore == nil || !mockRepo.GetAllCalledWith.DueBefore.Equal(dueBefore) {
+		t.Errorf("ожидалось due_before = %v, получено %v", dueBefore, mockRepo.GetAllCalledWith.DueBefore)
+	}
+	if !mockRepo.GetAllCalledWith.Overdue {
+		t.Error("ожидалось overdue = true")
 	}
 }
 
@@ -213,12 +325,28 @@ func TestUpdateTask_TableDriven(t *testing.T) {
 			expectedErr:    ErrEmptyTaskName,
 			wantUpdate:     false,
 		},
+		{
+			name:           "Ошибка: неизвестный статус",
+			existingStatus: StatusNew,
+			newName:        "Новое имя",
+			newStatus:      "review",
+			expectedErr:    ErrInvalidStatus,
+			wantUpdate:     false,
+		},
+		{
+			name:           "Ошибка: редактирование завершённой задачи",
+			existingStatus: StatusDone,
+			newName:        "Новое имя",
+			newStatus:      StatusDone,
+			expectedErr:    ErrDoneEdit,
+			wantUpdate:     false,
+		},
 		{
 			name:           "Ошибка: прыжок через статус",
 			existingStatus: StatusNew,
 			newName:        "Новое имя",
 			newStatus:      StatusDone,
-			expectedErr:    ErrNewTaskStatus,
+			expectedErr:    ErrTransitionNotAllowed,
 			wantUpdate:     false,
 		},
 	}
@@ -229,7 +357,10 @@ func TestUpdateTask_TableDriven(t *testing.T) {
 			}
 			service := NewService(mockRepo, nil)
 			const testDesc = "Описание задачи"
-			_, err := service.UpdateTask(context.Background(), 1, tt.newName, testDesc, tt.newStatus, nil)
+			_, err := service.UpdateTask(context.Background(), 1, UpdateTaskInput{
+				CreateTaskInput: CreateTaskInput{Name: tt.newName, Description: testDesc},
+				Status:          tt.newStatus,
+			})
 			if !errors.Is(err, tt.expectedErr) {
 				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
 			}
@@ -250,3 +381,130 @@ func TestUpdateTask_TableDriven(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateTask_DueDates(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(24 * time.Hour)
+	later := future.Add(24 * time.Hour)
+	tests := []struct {
+		name        string
+		startAt     *time.Time
+		dueAt       *time.Time
+		expectedErr error
+	}{
+		{name: "Без срока", expectedErr: nil},
+		{name: "Срок в будущем", startAt: &future, dueAt: &later, expectedErr: nil},
+		{name: "Ошибка: срок раньше создания", dueAt: &past, expectedErr: ErrDueBeforeCreated},
+		{name: "Ошибка: срок раньше начала", startAt: &later, dueAt: &future, expectedErr: ErrDueBeforeStart},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{}
+			service := NewService(mockRepo, nil)
+			_, err := service.CreateTask(context.Background(), CreateTaskInput{
+				Name:    "Отчёт",
+				StartAt: tt.startAt,
+				DueAt:   tt.dueAt,
+			})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if mockRepo.AddCalled != (tt.expectedErr == nil) {
+				t.Errorf("AddCalled = %v, а ожидалось %v", mockRepo.AddCalled, tt.expectedErr == nil)
+			}
+		})
+	}
+}
+
+func TestUpdateTask_DueBeforeCreated(t *testing.T) {
+	created := time.Now().Add(-48 * time.Hour)
+	due := created.Add(-time.Hour)
+	mockRepo := &MockRepository{
+		TaskToReturn: &Task{Status: StatusNew, Created: created},
+	}
+	service := NewService(mockRepo, nil)
+	_, err := service.UpdateTask(context.Background(), 1, UpdateTaskInput{
+		CreateTaskInput: CreateTaskInput{Name: "Отчёт", DueAt: &due},
+		Status:          StatusNew,
+	})
+	if !errors.Is(err, ErrDueBeforeCreated) {
+		t.Fatalf("ожидалась ошибка %v, получена %v", ErrDueBeforeCreated, err)
+	}
+	if mockRepo.UpdateCalled {
+		t.Error("репозиторий не должен был вызваться")
+	}
+}
+
+func TestService_StaleVersion(t *testing.T) {
+	ctx := context.Background()
+	stale, current := 2, 3
+	tests := []struct {
+		name        string
+		version     *int
+		expectedErr error
+	}{
+		{name: "Без версии", version: nil},
+		{name: "Текущая версия", version: &current},
+		{name: "Ошибка: устаревшая версия", version: &stale, expectedErr: ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{TaskToReturn: &Task{ID: 1, Status: StatusNew, Version: current}}
+			service := NewService(mockRepo, nil)
+			_, err := service.UpdateTask(ctx, 1, UpdateTaskInput{
+				CreateTaskInput: CreateTaskInput{Name: "Отчёт"},
+				Status:          StatusNew,
+				Version:         tt.version,
+			})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("обновление: ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if err := service.DeleteTask(ctx, 1, tt.version); !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("удаление: ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if mockRepo.UpdateCalled != (tt.expectedErr == nil) || (len(mockRepo.DeletedIDs) > 0) != (tt.expectedErr == nil) {
+				t.Errorf("запись в репозиторий: обновление %v, удаление %v", mockRepo.UpdateCalled, mockRepo.DeletedIDs)
+			}
+		})
+	}
+}
+
+func TestCreateTask_Priority(t *testing.T) {
+	tests := []struct {
+		name        string
+		priority    TaskPriority
+		expected    TaskPriority
+		expectedErr error
+	}{
+		{name: "Приоритет по умолчанию", expected: PriorityNormal},
+		{name: "Срочная задача", priority: PriorityUrgent, expected: PriorityUrgent},
+		{name: "Ошибка: неизвестный приоритет", priority: "asap", expectedErr: ErrInvalidPriority},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{}
+			service := NewService(mockRepo, nil)
+			task, err := service.CreateTask(context.Background(), CreateTaskInput{Name: "Задача", Priority: tt.priority})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if err == nil && task.Priority != tt.expected {
+				t.Errorf("ожидался приоритет %q, получен %q", tt.expected, task.Priority)
+			}
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	fields, err := ParseSort("-priority,created")
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	expected := []SortField{{Field: "priority", Desc: true}, {Field: "created"}}
+	if fmt.Sprint(fields) != fmt.Sprint(expected) {
+		t.Errorf("ожидалось %v, получено %v", expected, fields)
+	}
+	if _, err := ParseSort("priority,password"); !errors.Is(err, ErrInvalidSort) {
+		t.Errorf("ожидалась ошибка %v, получена %v", ErrInvalidSort, err)
+	}
+}
@@ -0,0 +1,42 @@
+package task
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizeGroupName returns the name as stored: NFC normalized, trimmed and
+// with inner whitespace collapsed, keeping the case the user typed.
+func normalizeGroupName(name string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(name)), " ")
+}
+
+// groupNameKey is the value group names are compared by, so that "Work",
+// "work " and "ｗｏｒｋ" are the same name.
+func groupNameKey(name string) string {
+	return strings.ToLower(norm.NFKC.String(normalizeGroupName(name)))
+}
+
+// slugify turns a group name into a URL slug: lower case letters and digits
+// separated by single dashes. Non-latin letters are kept as is.
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range groupNameKey(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	if b.Len() == 0 {
+		return "group"
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGroupNameKey(t *testing.T) {
+	for _, name := range []string{"Work", " work ", "WORK", "ｗｏｒｋ"} {
+		if key := groupNameKey(name); key != "work" {
+			t.Errorf("для %q получен ключ %q, ожидался \"work\"", name, key)
+		}
+	}
+	if normalizeGroupName("  Мои   задачи ") != "Мои задачи" {
+		t.Errorf("неверная нормализация: %q", normalizeGroupName("  Мои   задачи "))
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Work":         "work",
+		"Work Stuff!":  "work-stuff",
+		"  C++ / Go  ": "c-go",
+		"Мои задачи":   "мои-задачи",
+		"Release 2.0":  "release-2-0",
+		"!!!":          "group",
+		"ｗｏｒｋ　ｓｔｕｆｆ":   "work-stuff",
+	}
+	for name, want := range tests {
+		if got := slugify(name); got != want {
+			t.Errorf("slugify(%q) = %q, ожидалось %q", name, got, want)
+		}
+	}
+}
+
+func TestCreateGroup_Slug(t *testing.T) {
+	mockGroupRepo := &MockGroupRepository{GroupsToReturn: []Group{
+		{ID: 1, Name: "Work", Slug: "work"},
+		{ID: 2, Name: "Work", Slug: "work-2"},
+	}}
+	service := NewService(&MockRepository{}, mockGroupRepo)
+	g, err := service.CreateGroup(context.Background(), GroupInput{Name: "  work  "})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if g.Name != "work" || g.Slug != "work-3" {
+		t.Errorf("ожидалась группа work/work-3, получена %s/%s", g.Name, g.Slug)
+	}
+}
+
+func TestGetAllTasks_GroupSlug(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockGroupRepo := &MockGroupRepository{GroupsToReturn: []Group{{ID: 4, Name: "Work", Slug: "work"}}}
+	service := NewService(mockRepo, mockGroupRepo)
+	if _, err := service.GetAllTasks(context.Background(), TaskFilter{GroupSlug: "Work"}); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if filter := mockRepo.GetAllCalledWith; filter.GroupID == nil || *filter.GroupID != 4 {
+		t.Errorf("ожидался фильтр по группе 4, получен %+v", filter)
+	}
+	_, err := service.GetAllTasks(context.Background(), TaskFilter{GroupSlug: "home"})
+	if !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("ожидалась ошибка %v, получена %v", ErrGroupNotFound, err)
+	}
+}
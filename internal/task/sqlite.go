@@ -0,0 +1,419 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SQLiteRepository shares its queries with PostgresRepository through
+// sqliteConn. Tags, users, comments and attachments have no SQLite tables.
+type SQLiteRepository struct {
+	db sqliteConn
+}
+
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{
+		db: sqliteConn{db: db},
+	}
+}
+
+// taskFKError finds the missing reference by hand, as SQLite does not name
+// the violated constraint.
+func (r *SQLiteRepository) taskFKError(ctx context.Context, task *Task) error {
+	if task.GroupID != nil {
+		var exists bool
+		query := `SELECT EXISTS (SELECT 1 FROM groups WHERE id = $1)`
+		if err := r.db.QueryRowContext(ctx, query, *task.GroupID).Scan(&exists); err != nil {
+			return fmt.Errorf("sqlite: check group id=%d: %w", *task.GroupID, err)
+		}
+		if !exists {
+			return ErrGroupNotFound
+		}
+	}
+	return ErrParentNotFound
+}
+
+func (r *SQLiteRepository) Add(ctx context.Context, task *Task) error {
+	query := `
+		INSERT INTO tasks (
+			name, description, created, status, priority, group_id, start_at, due_at, parent_id, assignee_id,
+			recurrence, recurrence_start, completed_at, rank
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, (` + columnEndRank("$6", "$4") + `))
+		RETURNING id, rank, version
+	`
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		task.Name,
+		task.Description,
+		task.Created,
+		task.Status,
+		task.Priority,
+		task.GroupID,
+		task.StartAt,
+		task.DueAt,
+		task.ParentID,
+		task.AssigneeID,
+		task.Recurrence,
+		task.RecurrenceStart,
+		task.CompletedAt,
+	).Scan(&task.ID, &task.Rank, &task.Version)
+	if err != nil {
+		if isSQLiteForeignKey(err) {
+			return fmt.Errorf("sqlite.Add: insert task: %w", r.taskFKError(ctx, task))
+		}
+		return fmt.Errorf("sqlite.Add: insert task: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) GetById(ctx context.Context, id int) (*Task, error) {
+	var t Task
+	query := `SELECT ` + taskColumns + `
+		FROM tasks t
+		LEFT JOIN groups g ON t.group_id = g.id
+		WHERE t.id = $1 AND t.deleted_at IS NULL
+	`
+	err := scanTask(r.db.QueryRowContext(ctx, query, id), &t)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("sqlite.GetById: scan task id=%d: %w", id, err)
+	}
+	return &t, nil
+}
+
+func (r *SQLiteRepository) Count(ctx context.Context, filter TaskFilter) (int, error) {
+	// No task carries a tag, there is no tag repository for SQLite.
+	if len(filter.Tags) > 0 {
+		return 0, nil
+	}
+	where, args := taskConditions(filter)
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM tasks t`+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("sqlite.Count: count tasks: %w", err)
+	}
+	return count, nil
+}
+
+func (r *SQLiteRepository) GetAll(ctx context.Context, filter TaskFilter) ([]Task, error) {
+	if len(filter.Tags) > 0 {
+		return []Task{}, nil
+	}
+	where, args := taskConditions(filter)
+	query := `SELECT ` + taskColumns + `
+	FROM tasks t
+	LEFT JOIN groups g ON t.group_id = g.id
+	` + where + taskOrder(filter.Sort)
+	return r.queryTasks(ctx, "sqlite.GetAll", query, args...)
+}
+
+func (r *SQLiteRepository) queryTasks(ctx context.Context, op, query string, args ...any) ([]Task, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query tasks: %w", op, err)
+	}
+	defer rows.Close()
+
+	tasks := []Task{}
+	for rows.Next() {
+		var t Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, fmt.Errorf("%s: scan task row: %w", op, err)
+		}
+		tasks = append(tasks, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration: %w", op, err)
+	}
+	return tasks, nil
+}
+
+func (r *SQLiteRepository) Update(ctx context.Context, task *Task) error {
+	query := `
+		UPDATE tasks
+		SET name = $1, description = $2, status = $3, priority = $4, group_id = $5, start_at = $6, due_at = $7,
+			parent_id = $8, assignee_id = $9, recurrence = $10, recurrence_start = $11, completed_at = $12,
+			version = version + 1,
+			rank = CASE
+				WHEN status = $3 AND group_id IS NOT DISTINCT FROM $5 THEN rank
+				ELSE (` + columnEndRank("$5", "$3") + `)
+			END
+		WHERE id = $13 AND deleted_at IS NULL AND version = $14
+		RETURNING rank, version
+	`
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		task.Name,
+		task.Description,
+		task.Status,
+		task.Priority,
+		task.GroupID,
+		task.StartAt,
+		task.DueAt,
+		task.ParentID,
+		task.AssigneeID,
+		task.Recurrence,
+		task.RecurrenceStart,
+		task.CompletedAt,
+		task.ID,
+		task.Version,
+	).Scan(&task.Rank, &task.Version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return r.updateMissError(ctx, task.ID)
+		}
+		if isSQLiteForeignKey(err) {
+			return fmt.Errorf("sqlite.Update: update task: %w", r.taskFKError(ctx, task))
+		}
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+	return nil
+}
+
+// updateMissError tells a stale version from a missing task when an update
+// matched no row.
+func (r *SQLiteRepository) updateMissError(ctx context.Context, id int) error {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)`
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
+		return fmt.Errorf("sqlite: check task id=%d: %w", id, err)
+	}
+	if exists {
+		return ErrConflict
+	}
+	return ErrTaskNotFound
+}
+
+func (r *SQLiteRepository) Delete(ctx context.Context, id, version int) error {
+	query := `
+	UPDATE tasks SET deleted_at = $1, version = version + 1
+	WHERE id = $2 AND deleted_at IS NULL AND version = $3`
+	err := r.execTask(ctx, "failed to delete task", query, time.Now(), id, version)
+	if errors.Is(err, ErrTaskNotFound) {
+		return r.updateMissError(ctx, id)
+	}
+	return err
+}
+
+// execTask runs a single-row statement and reports ErrTaskNotFound when no
+// row matched.
+func (r *SQLiteRepository) execTask(ctx context.Context, op, query string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) GetTrash(ctx context.Context, deletedBefore time.Time) ([]Task, error) {
+	query := `SELECT ` + taskColumns + `
+	FROM tasks t
+	LEFT JOIN groups g ON t.group_id = g.id
+	WHERE t.deleted_at IS NOT NULL AND t.deleted_at < $1
+	ORDER BY t.deleted_at, t.id
+	`
+	return r.queryTasks(ctx, "sqlite.GetTrash", query, deletedBefore)
+}
+
+func (r *SQLiteRepository) GetTrashed(ctx context.Context, id int) (*Task, error) {
+	var t Task
+	query := `SELECT ` + taskColumns + `
+		FROM tasks t
+		LEFT JOIN groups g ON t.group_id = g.id
+		WHERE t.id = $1 AND t.deleted_at IS NOT NULL
+	`
+	err := scanTask(r.db.QueryRowContext(ctx, query, id), &t)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("sqlite.GetTrashed: scan task id=%d: %w", id, err)
+	}
+	return &t, nil
+}
+
+func (r *SQLiteRepository) Restore(ctx context.Context, id int) error {
+	query := `UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND deleted_at IS NOT NULL`
+	return r.execTask(ctx, "failed to restore task", query, id)
+}
+
+func (r *SQLiteRepository) Purge(ctx context.Context, id int) error {
+	query := `DELETE FROM tasks WHERE id = $1 AND deleted_at IS NOT NULL`
+	err := r.execTask(ctx, "failed to purge task", query, id)
+	if isSQLiteForeignKey(err) {
+		return fmt.Errorf("sqlite.Purge: %w", ErrTaskHasSubtasks)
+	}
+	return err
+}
+
+func (r *SQLiteRepository) ReassignGroup(ctx context.Context, fromGroupId int, toGroupId *int) (int, error) {
+	query := `UPDATE tasks SET group_id = $1, version = version + 1 WHERE group_id = $2 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, toGroupId, fromGroupId)
+	if err != nil {
+		if isSQLiteForeignKey(err) {
+			return 0, fmt.Errorf("sqlite.ReassignGroup: %w", ErrGroupNotFound)
+		}
+		return 0, fmt.Errorf("failed to reassign tasks: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
+
+// Move mirrors PostgresRepository.Move; the transaction already holds the
+// database write lock, so the task row needs no FOR UPDATE.
+func (r *SQLiteRepository) Move(ctx context.Context, id int, afterId, beforeId *int) (float64, error) {
+	var rank float64
+	err := r.db.inTx(ctx, func(tx sqliteConn) error {
+		var col taskColumn
+		query := `SELECT group_id, status FROM tasks WHERE id = $1 AND deleted_at IS NULL`
+		if err := tx.QueryRowContext(ctx, query, id).Scan(&col.groupId, &col.status); err != nil {
+			if err == sql.ErrNoRows {
+				return ErrTaskNotFound
+			}
+			return fmt.Errorf("sqlite.Move: get task id=%d: %w", id, err)
+		}
+
+		var ok bool
+		var err error
+		rank, ok, err = moveRank(ctx, tx, col, id, afterId, beforeId)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if err := rebalanceColumn(ctx, tx, col); err != nil {
+				return err
+			}
+			if rank, _, err = moveRank(ctx, tx, col, id, afterId, beforeId); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE tasks SET rank = $1, version = version + 1 WHERE id = $2`, rank, id); err != nil {
+			return fmt.Errorf("sqlite.Move: update rank: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rank, nil
+}
+
+// GetStats counts by status in the database and buckets the days in Go, in
+// the location of filter.From, as SQLite only knows UTC dates.
+func (r *SQLiteRepository) GetStats(ctx context.Context, filter StatsFilter) (*TaskStats, error) {
+	where, args := taskConditions(TaskFilter{GroupIDs: filter.GroupIDs})
+	args = append(args, filter.Now)
+	now := fmt.Sprintf("$%d", len(args))
+	query := `
+	SELECT t.status,
+		COUNT(*),
+		COUNT(*) FILTER (WHERE t.completed_at IS NULL),
+		COUNT(*) FILTER (WHERE t.completed_at IS NULL AND t.due_at < ` + now + `),
+		COALESCE(SUM((julianday(` + now + `) - julianday(t.created)) * 86400) FILTER (WHERE t.completed_at IS NULL), 0)
+	FROM tasks t` + where + `
+	GROUP BY t.status
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite.GetStats: query status counts: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &TaskStats{ByStatus: map[TaskStatus]int{}, From: filter.From, To: filter.To}
+	var openAge float64
+	for rows.Next() {
+		var (
+			status               TaskStatus
+			total, open, overdue int
+			age                  float64
+		)
+		if err := rows.Scan(&status, &total, &open, &overdue, &age); err != nil {
+			return nil, fmt.Errorf("sqlite.GetStats: scan status row: %w", err)
+		}
+		stats.ByStatus[status] = total
+		stats.Total += total
+		stats.Open += open
+		stats.Overdue += overdue
+		openAge += age
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sqlite.GetStats: status rows iteration: %w", err)
+	}
+	if stats.Open > 0 {
+		stats.AvgOpenAge = openAge / float64(stats.Open) / 3600
+	}
+
+	stats.Daily, err = r.dailyStats(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *SQLiteRepository) dailyStats(ctx context.Context, filter StatsFilter) ([]DailyStats, error) {
+	loc := filter.From.Location()
+	day := func(t time.Time) time.Time {
+		y, m, d := t.In(loc).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+	from, to := day(filter.From), day(filter.To)
+	daily := []DailyStats{}
+	index := map[time.Time]int{}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		index[d] = len(daily)
+		daily = append(daily, DailyStats{Day: d})
+	}
+
+	where, args := taskConditions(TaskFilter{GroupIDs: filter.GroupIDs})
+	args = append(args, from, to.AddDate(0, 0, 1))
+	start, end := fmt.Sprintf("$%d", len(args)-1), fmt.Sprintf("$%d", len(args))
+	query := `
+	SELECT t.created, t.completed_at
+	FROM tasks t` + where + ` AND (
+		(t.created >= ` + start + ` AND t.created < ` + end + `) OR
+		(t.completed_at >= ` + start + ` AND t.completed_at < ` + end + `)
+	)`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite.GetStats: query daily counts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var created time.Time
+		var completed *time.Time
+		if err := rows.Scan(&created, &completed); err != nil {
+			return nil, fmt.Errorf("sqlite.GetStats: scan daily row: %w", err)
+		}
+		if i, ok := index[day(created)]; ok {
+			daily[i].Created++
+		}
+		if completed != nil {
+			if i, ok := index[day(*completed)]; ok {
+				daily[i].Completed++
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sqlite.GetStats: daily rows iteration: %w", err)
+	}
+	return daily, nil
+}
@@ -0,0 +1,270 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SQLiteGroupRepository stores groups in the same shape as Postgres; the
+// owner is not checked, there is no users table.
+type SQLiteGroupRepository struct {
+	db sqliteConn
+}
+
+func NewSQLiteGroupRepository(db *sql.DB) *SQLiteGroupRepository {
+	return &SQLiteGroupRepository{
+		db: sqliteConn{db: db},
+	}
+}
+
+// groupWriteError translates the constraint failures of an insert or update.
+func groupWriteError(op string, err error) error {
+	if isSQLiteUnique(err) {
+		return fmt.Errorf("%s: %w", op, ErrNotUniqGroup)
+	}
+	if isSQLiteForeignKey(err) {
+		return fmt.Errorf("%s: %w", op, ErrParentGroupNotFound)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+func (r *SQLiteGroupRepository) Add(ctx context.Context, group *Group) error {
+	query := `
+		INSERT INTO groups (name, name_key, slug, parent_id, description, color, icon, owner_id, archived, wip_limit)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		group.Name,
+		groupNameKey(group.Name),
+		group.Slug,
+		group.ParentID,
+		group.Description,
+		group.Color,
+		group.Icon,
+		group.OwnerID,
+		group.Archived,
+		group.WIPLimit,
+	).Scan(&group.ID)
+	if err != nil {
+		return groupWriteError("sqlite.Add group", err)
+	}
+	return nil
+}
+
+func (r *SQLiteGroupRepository) getGroup(ctx context.Context, where string, arg any) (*Group, error) {
+	var group Group
+	query := `SELECT ` + groupColumns + ` FROM groups WHERE ` + where
+	err := scanGroup(r.db.QueryRowContext(ctx, query, arg), &group)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrGroupNotFound
+		}
+		return nil, fmt.Errorf("sqlite: scan group %v: %w", arg, err)
+	}
+	return &group, nil
+}
+
+func (r *SQLiteGroupRepository) GetById(ctx context.Context, id int) (*Group, error) {
+	return r.getGroup(ctx, `id = $1 AND deleted_at IS NULL`, id)
+}
+
+func (r *SQLiteGroupRepository) GetBySlug(ctx context.Context, slug string) (*Group, error) {
+	return r.getGroup(ctx, `slug = $1 AND deleted_at IS NULL`, slug)
+}
+
+func (r *SQLiteGroupRepository) GetTrashed(ctx context.Context, id int) (*Group, error) {
+	return r.getGroup(ctx, `id = $1 AND deleted_at IS NOT NULL`, id)
+}
+
+func (r *SQLiteGroupRepository) queryGroups(ctx context.Context, op, query string, args ...any) ([]Group, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s query group %w", op, err)
+	}
+	defer rows.Close()
+
+	groups := []Group{}
+	for rows.Next() {
+		var group Group
+		if err := scanGroup(rows, &group); err != nil {
+			return nil, fmt.Errorf("%s row group %w", op, err)
+		}
+		groups = append(groups, group)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s row iteration %w", op, err)
+	}
+	return groups, nil
+}
+
+func (r *SQLiteGroupRepository) GetAll(ctx context.Context) ([]Group, error) {
+	query := `SELECT ` + groupColumns + ` FROM groups WHERE deleted_at IS NULL ORDER BY name, id`
+	return r.queryGroups(ctx, "sqlite.GetAll", query)
+}
+
+// execGroup runs a single-row statement and reports ErrGroupNotFound when no
+// row matched.
+func (r *SQLiteGroupRepository) execGroup(ctx context.Context, query string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get group rows affected %w", err)
+	}
+	if rows == 0 {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteGroupRepository) Delete(ctx context.Context, id int) error {
+	query := `UPDATE groups SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	err := r.execGroup(ctx, query, time.Now(), id)
+	if err != nil && err != ErrGroupNotFound {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+	return err
+}
+
+func (r *SQLiteGroupRepository) Update(ctx context.Context, group *Group) error {
+	query := `
+		UPDATE groups
+		SET name = $1, name_key = $2, slug = $3, parent_id = $4, description = $5, color = $6, icon = $7,
+			owner_id = $8, archived = $9, wip_limit = $10
+		WHERE id = $11 AND deleted_at IS NULL
+	`
+	err := r.execGroup(
+		ctx,
+		query,
+		group.Name,
+		groupNameKey(group.Name),
+		group.Slug,
+		group.ParentID,
+		group.Description,
+		group.Color,
+		group.Icon,
+		group.OwnerID,
+		group.Archived,
+		group.WIPLimit,
+		group.ID,
+	)
+	if err != nil && err != ErrGroupNotFound {
+		return groupWriteError("sqlite.Update group", err)
+	}
+	return err
+}
+
+func (r *SQLiteGroupRepository) GetWorkflow(ctx context.Context, groupId int) (*Workflow, error) {
+	var raw sql.NullString
+	query := `SELECT workflow FROM groups WHERE id = $1`
+	err := r.db.QueryRowContext(ctx, query, groupId).Scan(&raw)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrGroupNotFound
+		}
+		return nil, fmt.Errorf("sqlite.GetWorkflow scan group id=%d: %w", groupId, err)
+	}
+	if !raw.Valid {
+		return nil, nil
+	}
+	var workflow Workflow
+	if err := json.Unmarshal([]byte(raw.String), &workflow); err != nil {
+		return nil, fmt.Errorf("sqlite.GetWorkflow decode group id=%d: %w", groupId, err)
+	}
+	return &workflow, nil
+}
+
+func (r *SQLiteGroupRepository) SetWorkflow(ctx context.Context, groupId int, workflow *Workflow) error {
+	var raw any
+	if workflow != nil {
+		data, err := json.Marshal(workflow)
+		if err != nil {
+			return fmt.Errorf("sqlite.SetWorkflow encode: %w", err)
+		}
+		raw = string(data)
+	}
+	query := `UPDATE groups SET workflow = $1 WHERE id = $2 AND deleted_at IS NULL`
+	err := r.execGroup(ctx, query, raw, groupId)
+	if err != nil && err != ErrGroupNotFound {
+		return fmt.Errorf("failed to update group workflow: %w", err)
+	}
+	return err
+}
+
+func (r *SQLiteGroupRepository) GetTrash(ctx context.Context, deletedBefore time.Time) ([]Group, error) {
+	query := `
+		SELECT ` + groupColumns + `
+		FROM groups
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		ORDER BY deleted_at, id
+	`
+	return r.queryGroups(ctx, "sqlite.GetTrash", query, deletedBefore)
+}
+
+func (r *SQLiteGroupRepository) Restore(ctx context.Context, id int) error {
+	query := `UPDATE groups SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	err := r.execGroup(ctx, query, id)
+	if isSQLiteUnique(err) {
+		return fmt.Errorf("sqlite.Restore group: %w", ErrNotUniqGroup)
+	}
+	if err != nil && err != ErrGroupNotFound {
+		return fmt.Errorf("failed to restore group: %w", err)
+	}
+	return err
+}
+
+func (r *SQLiteGroupRepository) Purge(ctx context.Context, id int) error {
+	query := `DELETE FROM groups WHERE id = $1 AND deleted_at IS NOT NULL`
+	err := r.execGroup(ctx, query, id)
+	if isSQLiteForeignKey(err) {
+		return fmt.Errorf("sqlite.Purge group: %w", ErrGroupHasTasks)
+	}
+	if err != nil && err != ErrGroupNotFound {
+		return fmt.Errorf("failed to purge group: %w", err)
+	}
+	return err
+}
+
+func (r *SQLiteGroupRepository) GetChildren(ctx context.Context, id int) ([]Group, error) {
+	query := `SELECT ` + groupColumns + ` FROM groups WHERE parent_id = $1 AND deleted_at IS NULL ORDER BY name, id`
+	return r.queryGroups(ctx, "sqlite.GetChildren", query, id)
+}
+
+func (r *SQLiteGroupRepository) GetDescendantIDs(ctx context.Context, id int) ([]int, error) {
+	query := `
+		WITH RECURSIVE descendants AS (
+			SELECT id FROM groups WHERE parent_id = $1 AND deleted_at IS NULL
+			UNION
+			SELECT g.id FROM groups g
+			JOIN descendants d ON g.parent_id = d.id
+			WHERE g.deleted_at IS NULL
+		)
+		SELECT id FROM descendants ORDER BY id
+	`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite.GetDescendantIDs query group %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("sqlite.GetDescendantIDs row group %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("sqlite.GetDescendantIDs row iteration %w", err)
+	}
+	return ids, nil
+}
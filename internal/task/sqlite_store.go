@@ -0,0 +1,182 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	sqlitemigrations "github.com/just4fun-xd/task-manager/migrations/sqlite"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// OpenSQLite opens the database file and brings its schema up to date.
+// Transactions take the write lock when they begin, so a read inside one is
+// never turned into a failing lock upgrade.
+func OpenSQLite(ctx context.Context, path string) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_txlock", "immediate")
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: open %s: %w", path, err)
+	}
+	if err := migrateSQLite(ctx, db, sqlitemigrations.FS); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// migrateSQLite applies the *.up.sql files not yet recorded in
+// schema_migrations, each in its own transaction.
+func migrateSQLite(ctx context.Context, db *sql.DB, migrations fs.FS) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`)
+	if err != nil {
+		return fmt.Errorf("sqlite: create schema_migrations: %w", err)
+	}
+	files, err := fs.Glob(migrations, "*.up.sql")
+	if err != nil {
+		return fmt.Errorf("sqlite: list migrations: %w", err)
+	}
+	slices.Sort(files)
+	for _, name := range files {
+		version, err := strconv.Atoi(strings.SplitN(name, "_", 2)[0])
+		if err != nil {
+			return fmt.Errorf("sqlite: migration %s: bad version: %w", name, err)
+		}
+		script, err := fs.ReadFile(migrations, name)
+		if err != nil {
+			return fmt.Errorf("sqlite: read migration %s: %w", name, err)
+		}
+		err = inSQLiteTx(ctx, db, func(tx *sql.Tx) error {
+			var applied bool
+			query := `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = ?)`
+			if err := tx.QueryRowContext(ctx, query, version).Scan(&applied); err != nil || applied {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("sqlite: migration %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+var pgPlaceholder = regexp.MustCompile(`\$(\d+)`)
+
+// sqliteConn lets the SQLite repositories share queries with the Postgres
+// ones: $N placeholders are rewritten to ?N and times are bound in UTC, so
+// that their stored text sorts chronologically.
+type sqliteConn struct {
+	db dbtx
+}
+
+func (c sqliteConn) bind(query string, args []any) (string, []any) {
+	bound := make([]any, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			bound[i] = v.UTC()
+		case *time.Time:
+			if v != nil {
+				bound[i] = v.UTC()
+			}
+		default:
+			bound[i] = arg
+		}
+	}
+	return pgPlaceholder.ReplaceAllString(query, "?$1"), bound
+}
+
+func (c sqliteConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	query, args = c.bind(query, args)
+	return c.db.ExecContext(ctx, query, args...)
+}
+
+func (c sqliteConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	query, args = c.bind(query, args)
+	return c.db.QueryContext(ctx, query, args...)
+}
+
+func (c sqliteConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	query, args = c.bind(query, args)
+	return c.db.QueryRowContext(ctx, query, args...)
+}
+
+// inTx runs fn in the transaction the connection already belongs to, or in a
+// new one.
+func (c sqliteConn) inTx(ctx context.Context, fn func(tx sqliteConn) error) error {
+	if _, ok := c.db.(*sql.Tx); ok {
+		return fn(c)
+	}
+	return inSQLiteTx(ctx, c.db.(*sql.DB), func(tx *sql.Tx) error {
+		return fn(sqliteConn{db: tx})
+	})
+}
+
+func inSQLiteTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sqlite: begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("sqlite: commit transaction: %w", err)
+	}
+	return nil
+}
+
+// sqliteErrorCode returns the extended result code of a SQLite error, or 0.
+func sqliteErrorCode(err error) int {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()
+	}
+	return 0
+}
+
+func isSQLiteUnique(err error) bool {
+	code := sqliteErrorCode(err)
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+func isSQLiteForeignKey(err error) bool {
+	return sqliteErrorCode(err) == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+}
+
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteStore(db *sql.DB) *SQLiteStore {
+	return &SQLiteStore{
+		db: db,
+	}
+}
+
+func (s *SQLiteStore) WithTx(ctx context.Context, fn func(repos TxRepos) error) error {
+	return inSQLiteTx(ctx, s.db, func(tx *sql.Tx) error {
+		conn := sqliteConn{db: tx}
+		return fn(TxRepos{Tasks: &SQLiteRepository{db: conn}, Groups: &SQLiteGroupRepository{db: conn}})
+	})
+}
@@ -0,0 +1,84 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newSQLiteService(t *testing.T) (*Service, *SQLiteRepository, *SQLiteGroupRepository) {
+	t.Helper()
+	db, err := OpenSQLite(context.Background(), filepath.Join(t.TempDir(), "tasks.db"))
+	if err != nil {
+		t.Fatalf("не удалось открыть базу: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	repo, groups := NewSQLiteRepository(db), NewSQLiteGroupRepository(db)
+	return NewService(repo, groups, WithStore(NewSQLiteStore(db)), WithStats(repo)), repo, groups
+}
+
+func TestOpenSQLite_Reopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	for range 2 {
+		db, err := OpenSQLite(ctx, path)
+		if err != nil {
+			t.Fatalf("миграции должны применяться повторно без ошибок: %v", err)
+		}
+		db.Close()
+	}
+}
+
+func TestSQLiteService_Tasks(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newSQLiteService(t)
+	group, err := service.CreateGroup(ctx, GroupInput{Name: "Работа"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, due := time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour)
+	var ids []int
+	for _, name := range []string{"А", "Б", "В"} {
+		task := &Task{Name: name, Status: StatusInProgress, Priority: PriorityNormal, Created: created, GroupID: &group.ID, DueAt: &due}
+		if err := repo.Add(ctx, task); err != nil {
+			t.Fatalf("неожиданная ошибка: %v", err)
+		}
+		ids = append(ids, task.ID)
+	}
+	if _, err := service.MoveTask(ctx, ids[2], nil, &ids[0]); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	tasks, err := repo.GetAll(ctx, TaskFilter{GroupID: &group.ID, Overdue: true, Sort: []SortField{{Field: "rank"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, task := range tasks {
+		names = append(names, task.Name)
+	}
+	if fmt.Sprint(names) != "[В А Б]" {
+		t.Errorf("получен порядок %v, ожидался [В А Б]", names)
+	}
+
+	in := UpdateTaskInput{CreateTaskInput: CreateTaskInput{Name: "Б", GroupID: &group.ID, DueAt: &due}, Status: StatusDone}
+	if _, err := service.UpdateTask(ctx, ids[1], in); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	stats, err := service.GetGroupStats(ctx, group.ID, false, time.Now().AddDate(0, 0, -1), time.Now())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if stats.Total != 3 || stats.Open != 2 || stats.Overdue != 2 || stats.ByStatus[StatusDone] != 1 {
+		t.Errorf("неверная статистика: %+v", stats)
+	}
+	var daily DailyStats
+	for _, d := range stats.Daily {
+		daily.Created += d.Created
+		daily.Completed += d.Completed
+	}
+	if daily.Created != 3 || daily.Completed != 1 {
+		t.Errorf("неверная статистика по дням: %+v", stats.Daily)
+	}
+}
@@ -0,0 +1,37 @@
+package task
+
+import (
+	"context"
+	"time"
+)
+
+type StatsFilter struct {
+	// GroupIDs limits the statistics to tasks of these groups; empty means
+	// all tasks.
+	GroupIDs []int
+	From     time.Time
+	To       time.Time
+	Now      time.Time
+}
+
+type DailyStats struct {
+	Day       time.Time `json:"day"`
+	Created   int       `json:"created"`
+	Completed int       `json:"completed"`
+}
+
+type TaskStats struct {
+	ByStatus map[TaskStatus]int `json:"by_status"`
+	Total    int                `json:"total"`
+	Open     int                `json:"open"`
+	Overdue  int                `json:"overdue"`
+	// AvgOpenAge is the average age of open tasks in hours.
+	AvgOpenAge float64      `json:"avg_open_age_hours"`
+	From       time.Time    `json:"from"`
+	To         time.Time    `json:"to"`
+	Daily      []DailyStats `json:"daily"`
+}
+
+type StatsRepository interface {
+	GetStats(ctx context.Context, filter StatsFilter) (*TaskStats, error)
+}
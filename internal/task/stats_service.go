@@ -0,0 +1,68 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const maxStatsDays = 366
+
+var (
+	ErrStatsDisabled     = errors.New("statistics are not configured")
+	ErrInvalidStatsRange = errors.New("invalid statistics range")
+)
+
+func (s *Service) GetStats(ctx context.Context, from, to time.Time) (*TaskStats, error) {
+	return s.getStats(ctx, StatsFilter{From: from, To: to})
+}
+
+// GetGroupStats returns the statistics of a group, including its descendant
+// groups when recursive is set. Every state of the group workflow is present
+// in ByStatus, even without tasks.
+func (s *Service) GetGroupStats(ctx context.Context, id int, recursive bool, from, to time.Time) (*TaskStats, error) {
+	if _, err := s.GetGroup(ctx, id); err != nil {
+		return nil, err
+	}
+	filter := StatsFilter{GroupIDs: []int{id}, From: from, To: to}
+	if recursive {
+		descendants, err := s.groups.GetDescendantIDs(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get child groups: %w", err)
+		}
+		filter.GroupIDs = append(filter.GroupIDs, descendants...)
+	}
+	stats, err := s.getStats(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	workflow, err := s.workflowFor(ctx, &id)
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range workflow.States {
+		if _, ok := stats.ByStatus[state]; !ok {
+			stats.ByStatus[state] = 0
+		}
+	}
+	return stats, nil
+}
+
+func (s *Service) getStats(ctx context.Context, filter StatsFilter) (*TaskStats, error) {
+	if s.stats == nil {
+		return nil, ErrStatsDisabled
+	}
+	if filter.To.Before(filter.From) {
+		return nil, fmt.Errorf("%w: to is before from", ErrInvalidStatsRange)
+	}
+	if filter.To.Sub(filter.From) > maxStatsDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: more than %d days", ErrInvalidStatsRange, maxStatsDays)
+	}
+	filter.Now = time.Now()
+	stats, err := s.stats.GetStats(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statistics: %w", err)
+	}
+	return stats, nil
+}
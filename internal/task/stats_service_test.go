@@ -0,0 +1,84 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type MockStatsRepository struct {
+	Filter *StatsFilter
+}
+
+func (m *MockStatsRepository) GetStats(ctx context.Context, filter StatsFilter) (*TaskStats, error) {
+	m.Filter = &filter
+	return &TaskStats{ByStatus: map[TaskStatus]int{StatusNew: 2}, Total: 2, Open: 2}, nil
+}
+
+func TestGetStats_Range(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		from, to    time.Time
+		expectedErr error
+	}{
+		{name: "Последний месяц", from: now.AddDate(0, -1, 0), to: now},
+		{name: "Ошибка: конец раньше начала", from: now, to: now.Add(-time.Hour), expectedErr: ErrInvalidStatsRange},
+		{name: "Ошибка: больше года", from: now.AddDate(-2, 0, 0), to: now, expectedErr: ErrInvalidStatsRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStats := &MockStatsRepository{}
+			service := NewService(&MockRepository{}, &MockGroupRepository{}, WithStats(mockStats))
+			_, err := service.GetStats(context.Background(), tt.from, tt.to)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if (mockStats.Filter != nil) != (tt.expectedErr == nil) {
+				t.Errorf("репозиторий вызван = %v, а ожидалось %v", mockStats.Filter != nil, tt.expectedErr == nil)
+			}
+		})
+	}
+}
+
+func TestGetStats_Disabled(t *testing.T) {
+	service := NewService(&MockRepository{}, &MockGroupRepository{})
+	if _, err := service.GetStats(context.Background(), time.Now().Add(-time.Hour), time.Now()); !errors.Is(err, ErrStatsDisabled) {
+		t.Errorf("ожидалась ошибка %v, получена %v", ErrStatsDisabled, err)
+	}
+}
+
+func TestGetGroupStats(t *testing.T) {
+	mockStats := &MockStatsRepository{}
+	mockGroupRepo := &MockGroupRepository{GroupToReturn: &Group{ID: 1, Name: "Работа"}, DescendantIDs: []int{2, 3}}
+	service := NewService(&MockRepository{}, mockGroupRepo, WithStats(mockStats))
+	stats, err := service.GetGroupStats(context.Background(), 1, true, time.Now().AddDate(0, 0, -7), time.Now())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(mockStats.Filter.GroupIDs) != 3 {
+		t.Errorf("ожидалась статистика по 3 группам, получено %v", mockStats.Filter.GroupIDs)
+	}
+	for _, state := range DefaultWorkflow().States {
+		if _, ok := stats.ByStatus[state]; !ok {
+			t.Errorf("в статистике нет статуса %s", state)
+		}
+	}
+	if stats.ByStatus[StatusNew] != 2 {
+		t.Errorf("ожидалось 2 новые задачи, получено %d", stats.ByStatus[StatusNew])
+	}
+}
+
+func TestUpdateTask_SetsCompletedAt(t *testing.T) {
+	mockRepo := &MockRepository{TaskToReturn: &Task{ID: 1, Name: "Задача", Status: StatusInProgress, Created: time.Now()}}
+	service := NewService(mockRepo, &MockGroupRepository{})
+	in := UpdateTaskInput{CreateTaskInput: CreateTaskInput{Name: "Задача"}, Status: StatusDone}
+	updated, err := service.UpdateTask(context.Background(), 1, in)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if updated.CompletedAt == nil {
+		t.Error("у завершённой задачи не заполнено время завершения")
+	}
+}
@@ -0,0 +1,57 @@
+package task
+
+import "context"
+
+// TxRepos are the repositories a Store binds to one transaction. A store
+// leaves nil the ones it has no tables for.
+type TxRepos struct {
+	Tasks        TaskRepository
+	Groups       GroupRepository
+	Events       EventRepository
+	Dependencies DependencyRepository
+	Tags         TagRepository
+}
+
+// Store runs fn with repositories bound to one transaction; the transaction
+// is committed when fn returns nil and rolled back otherwise.
+type Store interface {
+	WithTx(ctx context.Context, fn func(repos TxRepos) error) error
+}
+
+func WithStore(store Store) Option {
+	return func(s *Service) {
+		s.store = store
+	}
+}
+
+// withTx runs fn inside a transaction of the configured store. Without a
+// store fn gets the service repositories and runs without atomicity.
+func (s *Service) withTx(ctx context.Context, fn func(tasks TaskRepository, groups GroupRepository) error) error {
+	return s.atomically(ctx, func(tx *Service) error {
+		return fn(tx.repo, tx.groups)
+	})
+}
+
+// atomically runs fn with a copy of the service whose repositories are bound
+// to one transaction, so the checks of a multi-step operation, its writes and
+// their audit events see the same data and are committed together. Optional
+// repositories the service was built without stay disabled in the copy.
+func (s *Service) atomically(ctx context.Context, fn func(tx *Service) error) error {
+	if s.store == nil {
+		return fn(s)
+	}
+	return s.store.WithTx(ctx, func(r TxRepos) error {
+		tx := *s
+		tx.repo, tx.groups, tx.store = r.Tasks, r.Groups, nil
+		if s.events != nil && r.Events != nil {
+			tx.events = r.Events
+		}
+		if s.dependencies != nil && r.Dependencies != nil {
+			tx.dependencies = r.Dependencies
+		}
+		if s.tags != nil && r.Tags != nil {
+			tx.tags = r.Tags
+		}
+		return fn(&tx)
+	})
+}
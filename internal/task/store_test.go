@@ -0,0 +1,146 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// MockStore hands fn its own repositories, so a test can tell the writes made
+// in the transaction from the ones made outside of it; CommitErr fails the
+// commit after fn succeeds.
+type MockStore struct {
+	Tasks        *MockRepository
+	Groups       *MockGroupRepository
+	Events       *MockEventRepository
+	Dependencies *MockDependencyRepository
+	Tags         *MockTagRepository
+	Calls        int
+	CommitErr    error
+}
+
+func (m *MockStore) WithTx(ctx context.Context, fn func(repos TxRepos) error) error {
+	m.Calls++
+	repos := TxRepos{Tasks: m.Tasks, Groups: m.Groups}
+	if m.Events != nil {
+		repos.Events = m.Events
+	}
+	if m.Dependencies != nil {
+		repos.Dependencies = m.Dependencies
+	}
+	if m.Tags != nil {
+		repos.Tags = m.Tags
+	}
+	if err := fn(repos); err != nil {
+		return err
+	}
+	return m.CommitErr
+}
+
+func TestService_WritesInTransaction(t *testing.T) {
+	ctx := context.Background()
+	rule := "FREQ=WEEKLY"
+	due := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	task := func() *Task {
+		return &Task{ID: 1, Name: "Задача", Status: StatusNew, Priority: PriorityNormal, Created: time.Now()}
+	}
+	tests := []struct {
+		name  string
+		run   func(s *Service) error
+		wrote func(store *MockStore) bool
+	}{
+		{
+			name: "Создание задачи",
+			run: func(s *Service) error {
+				_, err := s.CreateTask(ctx, CreateTaskInput{Name: "Задача"})
+				return err
+			},
+			wrote: func(store *MockStore) bool { return store.Tasks.AddCalled },
+		},
+		{
+			name: "Обновление задачи",
+			run: func(s *Service) error {
+				_, err := s.UpdateTask(ctx, 1, UpdateTaskInput{CreateTaskInput: CreateTaskInput{Name: "Отчёт"}, Status: StatusNew})
+				return err
+			},
+			wrote: func(store *MockStore) bool { return store.Tasks.UpdateCalled },
+		},
+		{
+			name: "Удаление задачи",
+			run: func(s *Service) error {
+				return s.DeleteTask(ctx, 1, nil)
+			},
+			wrote: func(store *MockStore) bool { return len(store.Tasks.DeletedIDs) > 0 },
+		},
+		{
+			name: "Перемещение задачи",
+			run: func(s *Service) error {
+				before := 2
+				_, err := s.MoveTask(ctx, 1, nil, &before)
+				return err
+			},
+			wrote: func(store *MockStore) bool { return store.Tasks.MoveCalled },
+		},
+		{
+			name: "Добавление зависимости",
+			run: func(s *Service) error {
+				return s.AddDependency(ctx, 1, 2)
+			},
+			wrote: func(store *MockStore) bool { return store.Dependencies.Added != nil },
+		},
+		{
+			name: "Завершение повторяющейся задачи",
+			run: func(s *Service) error {
+				_, err := s.UpdateTask(ctx, 3, UpdateTaskInput{
+					CreateTaskInput: CreateTaskInput{Name: "Отчёт", DueAt: &due, Recurrence: &rule},
+					Status:          StatusDone,
+				})
+				return err
+			},
+			wrote: func(store *MockStore) bool { return store.Tags.AssignedTag == 7 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outside := &MockRepository{}
+			recurring := task()
+			recurring.ID, recurring.Status = 3, StatusInProgress
+			recurring.DueAt, recurring.Recurrence, recurring.RecurrenceStart = &due, &rule, &due
+			tags := map[int][]Tag{3: {{ID: 7, Name: "отчёт"}}}
+			store := &MockStore{
+				Tasks:        &MockRepository{TasksByID: map[int]*Task{1: task(), 2: task(), 3: recurring}},
+				Groups:       &MockGroupRepository{},
+				Events:       &MockEventRepository{},
+				Dependencies: &MockDependencyRepository{},
+				Tags:         &MockTagRepository{TagsByTask: tags},
+			}
+			events := &MockEventRepository{}
+			dependencies := &MockDependencyRepository{}
+			outsideTags := &MockTagRepository{TagsByTask: tags}
+			service := NewService(outside, &MockGroupRepository{}, WithStore(store), WithEvents(events),
+				WithDependencies(dependencies), WithTags(outsideTags))
+
+			if err := tt.run(service); err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if store.Calls != 1 || !tt.wrote(store) {
+				t.Errorf("запись должна идти через транзакцию, транзакций: %d", store.Calls)
+			}
+			if outside.AddCalled || outside.UpdateCalled || outside.MoveCalled || len(outside.DeletedIDs) > 0 {
+				t.Error("запись мимо транзакции")
+			}
+			if dependencies.Added != nil || outsideTags.AssignedTag != 0 {
+				t.Error("зависимость или тег записаны мимо транзакции")
+			}
+			if len(events.Events) != 0 {
+				t.Errorf("событие записано мимо транзакции: %v", events.Events)
+			}
+
+			store.CommitErr = errors.New("commit failed")
+			if err := tt.run(service); !errors.Is(err, store.CommitErr) {
+				t.Errorf("ожидалась ошибка %v, получена %v", store.CommitErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+func (s *Service) GetSubtasks(ctx context.Context, id int) ([]Task, error) {
+	if _, err := s.GetTask(ctx, id); err != nil {
+		return nil, err
+	}
+	children, _, err := s.subtasks(ctx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+	return children, nil
+}
+
+// GetTaskDetails returns the task with its subtask progress and, when
+// includeSubtasks is set, the whole subtask tree.
+func (s *Service) GetTaskDetails(ctx context.Context, id int, includeSubtasks bool) (*Task, error) {
+	task, err := s.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadSubtasks(ctx, task, includeSubtasks, map[int]*Workflow{}); err != nil {
+		return nil, err
+	}
+	tasks := []Task{*task}
+	if err := s.attachTags(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return &tasks[0], nil
+}
+
+func (s *Service) loadSubtasks(ctx context.Context, task *Task, recursive bool, cache map[int]*Workflow) error {
+	children, progress, err := s.subtasks(ctx, task.ID, cache)
+	if err != nil {
+		return err
+	}
+	task.Progress = progress
+	if !recursive {
+		return nil
+	}
+	for i := range children {
+		if err := s.loadSubtasks(ctx, &children[i], true, cache); err != nil {
+			return err
+		}
+	}
+	task.Subtasks = children
+	return nil
+}
+
+func (s *Service) subtasks(ctx context.Context, id int, cache map[int]*Workflow) ([]Task, *Progress, error) {
+	children, err := s.repo.GetAll(ctx, TaskFilter{ParentID: &id})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get subtasks: %w", err)
+	}
+	if len(children) == 0 {
+		return children, nil, nil
+	}
+	if cache == nil {
+		cache = map[int]*Workflow{}
+	}
+	progress := &Progress{Total: len(children)}
+	for _, child := range children {
+		workflow, err := s.cachedWorkflow(ctx, cache, child.GroupID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if workflow.IsTerminal(child.Status) {
+			progress.Done++
+		}
+	}
+	return children, progress, nil
+}
+
+// checkParent makes sure parentId exists, is still open and is not the task
+// itself or one of its descendants; id is 0 for a task that is not stored yet.
+func (s *Service) checkParent(ctx context.Context, id, parentId int) error {
+	for next := &parentId; next != nil; {
+		if *next == id {
+			return ErrInvalidParent
+		}
+		parent, err := s.repo.GetById(ctx, *next)
+		if err != nil {
+			if errors.Is(err, ErrTaskNotFound) {
+				return ErrParentNotFound
+			}
+			return fmt.Errorf("failed to get parent task: %w", err)
+		}
+		if parent.ID == parentId {
+			if err := s.checkParentOpen(ctx, parent); err != nil {
+				return err
+			}
+		}
+		if id == 0 {
+			return nil
+		}
+		next = parent.ParentID
+	}
+	return nil
+}
+
+// checkParentOpen refuses a parent in a terminal state, which could not be
+// closed again while the new subtask is open.
+func (s *Service) checkParentOpen(ctx context.Context, parent *Task) error {
+	workflow, err := s.workflowFor(ctx, parent.GroupID)
+	if err != nil {
+		return err
+	}
+	if workflow.IsTerminal(parent.Status) {
+		return ErrParentClosed
+	}
+	return nil
+}
+
+func (s *Service) cachedWorkflow(ctx context.Context, cache map[int]*Workflow, groupId *int) (*Workflow, error) {
+	key := 0
+	if groupId != nil {
+		key = *groupId
+	}
+	if workflow, ok := cache[key]; ok {
+		return workflow, nil
+	}
+	workflow, err := s.workflowFor(ctx, groupId)
+	if err != nil {
+		return nil, err
+	}
+	cache[key] = workflow
+	return workflow, nil
+}
@@ -0,0 +1,153 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestUpdateTask_OpenSubtasks(t *testing.T) {
+	tests := []struct {
+		name        string
+		children    []Task
+		expectedErr error
+	}{
+		{name: "Без подзадач", expectedErr: nil},
+		{name: "Все подзадачи закрыты", children: []Task{{ID: 2, Status: StatusDone}}, expectedErr: nil},
+		{
+			name:        "Ошибка: открытая подзадача",
+			children:    []Task{{ID: 2, Status: StatusDone}, {ID: 3, Status: StatusNew}},
+			expectedErr: ErrOpenSubtasks,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{
+				TaskToReturn:  &Task{ID: 1, Status: StatusInProgress},
+				TasksToReturn: tt.children,
+			}
+			service := NewService(mockRepo, nil)
+			task, err := service.UpdateTask(context.Background(), 1, UpdateTaskInput{
+				CreateTaskInput: CreateTaskInput{Name: "Эпик"},
+				Status:          StatusDone,
+			})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if mockRepo.UpdateCalled != (tt.expectedErr == nil) {
+				t.Errorf("UpdateCalled = %v, а ожидалось %v", mockRepo.UpdateCalled, tt.expectedErr == nil)
+			}
+			if err == nil && len(tt.children) > 0 {
+				if task.Progress == nil || task.Progress.Total != len(tt.children) || task.Progress.Done != len(tt.children) {
+					t.Errorf("неверный прогресс: %+v", task.Progress)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateTask_ParentCycle(t *testing.T) {
+	parentID := 1
+	childID := 2
+	mockRepo := &MockRepository{
+		TasksByID: map[int]*Task{
+			1: {ID: 1, Status: StatusNew},
+			2: {ID: 2, Status: StatusNew, ParentID: &parentID},
+		},
+	}
+	service := NewService(mockRepo, nil)
+	_, err := service.UpdateTask(context.Background(), 1, UpdateTaskInput{
+		CreateTaskInput: CreateTaskInput{Name: "Эпик", ParentID: &childID},
+		Status:          StatusNew,
+	})
+	if !errors.Is(err, ErrInvalidParent) {
+		t.Fatalf("ожидалась ошибка %v, получена %v", ErrInvalidParent, err)
+	}
+	if mockRepo.UpdateCalled {
+		t.Error("репозиторий не должен был вызваться")
+	}
+}
+
+func TestCreateTask_ParentNotFound(t *testing.T) {
+	mockRepo := &MockRepository{TasksByID: map[int]*Task{}}
+	service := NewService(mockRepo, nil)
+	parentID := 42
+	_, err := service.CreateTask(context.Background(), CreateTaskInput{Name: "Подзадача", ParentID: &parentID})
+	if !errors.Is(err, ErrParentNotFound) {
+		t.Fatalf("ожидалась ошибка %v, получена %v", ErrParentNotFound, err)
+	}
+	if mockRepo.AddCalled {
+		t.Error("репозиторий не должен был вызваться")
+	}
+}
+
+func TestSubtask_ClosedParent(t *testing.T) {
+	parentID := 1
+	actions := []struct {
+		name string
+		run  func(s *Service) error
+	}{
+		{name: "Создание", run: func(s *Service) error {
+			_, err := s.CreateTask(context.Background(), CreateTaskInput{Name: "Подзадача", ParentID: &parentID})
+			return err
+		}},
+		{name: "Смена родителя", run: func(s *Service) error {
+			_, err := s.UpdateTask(context.Background(), 2, UpdateTaskInput{
+				CreateTaskInput: CreateTaskInput{Name: "Подзадача", ParentID: &parentID},
+				Status:          StatusNew,
+			})
+			return err
+		}},
+		{name: "Восстановление", run: func(s *Service) error {
+			_, err := s.RestoreTask(context.Background(), 3)
+			return err
+		}},
+	}
+	parents := []struct {
+		status      TaskStatus
+		expectedErr error
+	}{
+		{status: StatusInProgress},
+		{status: StatusDone, expectedErr: ErrParentClosed},
+	}
+	for _, a := range actions {
+		for _, p := range parents {
+			t.Run(a.name+" "+string(p.status), func(t *testing.T) {
+				mockRepo := &MockRepository{
+					TasksByID: map[int]*Task{
+						1: {ID: 1, Status: p.status},
+						2: {ID: 2, Status: StatusNew},
+					},
+					TrashToReturn: []Task{{ID: 3, Status: StatusNew, ParentID: &parentID}},
+				}
+				service := NewService(mockRepo, &MockGroupRepository{})
+				err := a.run(service)
+				if !errors.Is(err, p.expectedErr) {
+					t.Fatalf("ожидалась ошибка %v, получена %v", p.expectedErr, err)
+				}
+				written := mockRepo.AddCalled || mockRepo.UpdateCalled || mockRepo.RestoreCalled
+				if written != (p.expectedErr == nil) {
+					t.Errorf("запись в репозиторий: %v, а ожидалось %v", written, p.expectedErr == nil)
+				}
+			})
+		}
+	}
+}
+
+func TestGetTaskDetails_Progress(t *testing.T) {
+	mockRepo := &MockRepository{
+		TaskToReturn:  &Task{ID: 1, Status: StatusInProgress},
+		TasksToReturn: []Task{{ID: 2, Status: StatusDone}, {ID: 3, Status: StatusInProgress}},
+	}
+	service := NewService(mockRepo, nil)
+	task, err := service.GetTaskDetails(context.Background(), 1, false)
+	if err != nil {
+		t.Fatalf("не ожидалось ошибки, получена: %v", err)
+	}
+	if task.Progress == nil || task.Progress.Done != 1 || task.Progress.Total != 2 {
+		t.Errorf("ожидался прогресс 1/2, получен %+v", task.Progress)
+	}
+	if task.Subtasks != nil {
+		t.Error("подзадачи не запрашивались")
+	}
+}
@@ -0,0 +1,19 @@
+package task
+
+import "context"
+
+type Tag struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type TagRepository interface {
+	Add(ctx context.Context, tag *Tag) error
+	GetAll(ctx context.Context) ([]Tag, error)
+	GetById(ctx context.Context, id int) (*Tag, error)
+	Update(ctx context.Context, tag *Tag) error
+	Delete(ctx context.Context, id int) error
+	Assign(ctx context.Context, taskId, tagId int) error
+	Unassign(ctx context.Context, taskId, tagId int) error
+	GetByTasks(ctx context.Context, taskIds []int) (map[int][]Tag, error)
+}
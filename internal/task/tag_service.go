@@ -0,0 +1,166 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrTagsDisabled = errors.New("tags are not configured")
+	ErrEmptyTagName = errors.New("tag name cannot be empty")
+	ErrTagNameLong  = errors.New("tag name is too long")
+	ErrNotUniqTag   = errors.New("tag has not unique name")
+	ErrTagNotFound  = errors.New("tag not found")
+)
+
+// maxTagNameLen matches the tags.name column.
+const maxTagNameLen = 64
+
+func tagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrEmptyTagName
+	}
+	if utf8.RuneCountInString(name) > maxTagNameLen {
+		return "", ErrTagNameLong
+	}
+	return name, nil
+}
+
+func (s *Service) CreateTag(ctx context.Context, name string) (*Tag, error) {
+	if s.tags == nil {
+		return nil, ErrTagsDisabled
+	}
+	name, err := tagName(name)
+	if err != nil {
+		return nil, err
+	}
+	tag := &Tag{
+		Name: name,
+	}
+	if err := s.tags.Add(ctx, tag); err != nil {
+		return nil, fmt.Errorf("failed to add tag: %w", err)
+	}
+	return tag, nil
+}
+
+func (s *Service) GetTag(ctx context.Context, id int) (*Tag, error) {
+	if s.tags == nil {
+		return nil, ErrTagsDisabled
+	}
+	if id <= 0 {
+		return nil, fmt.Errorf("incorrect id: %d", id)
+	}
+	tag, err := s.tags.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return tag, nil
+}
+
+func (s *Service) ListTags(ctx context.Context) ([]Tag, error) {
+	if s.tags == nil {
+		return nil, ErrTagsDisabled
+	}
+	tags, err := s.tags.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all tags: %w", err)
+	}
+	return tags, nil
+}
+
+func (s *Service) UpdateTag(ctx context.Context, id int, name string) (*Tag, error) {
+	if s.tags == nil {
+		return nil, ErrTagsDisabled
+	}
+	if id <= 0 {
+		return nil, fmt.Errorf("incorrect id: %d", id)
+	}
+	name, err := tagName(name)
+	if err != nil {
+		return nil, err
+	}
+	tag := &Tag{
+		ID:   id,
+		Name: name,
+	}
+	if err := s.tags.Update(ctx, tag); err != nil {
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+	return tag, nil
+}
+
+func (s *Service) DeleteTag(ctx context.Context, id int) error {
+	if s.tags == nil {
+		return ErrTagsDisabled
+	}
+	if id <= 0 {
+		return fmt.Errorf("incorrect id: %d", id)
+	}
+	if err := s.tags.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) AssignTag(ctx context.Context, taskId, tagId int) error {
+	if s.tags == nil {
+		return ErrTagsDisabled
+	}
+	if _, err := s.GetTask(ctx, taskId); err != nil {
+		return err
+	}
+	if _, err := s.GetTag(ctx, tagId); err != nil {
+		return err
+	}
+	if err := s.tags.Assign(ctx, taskId, tagId); err != nil {
+		return fmt.Errorf("failed to assign tag: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) UnassignTag(ctx context.Context, taskId, tagId int) error {
+	if s.tags == nil {
+		return ErrTagsDisabled
+	}
+	if _, err := s.GetTask(ctx, taskId); err != nil {
+		return err
+	}
+	if err := s.tags.Unassign(ctx, taskId, tagId); err != nil {
+		return fmt.Errorf("failed to unassign tag: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) attachTags(ctx context.Context, tasks []Task) error {
+	if s.tags == nil || len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]int, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+	byTask, err := s.tags.GetByTasks(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get task tags: %w", err)
+	}
+	for i := range tasks {
+		tasks[i].Tags = byTask[tasks[i].ID]
+	}
+	return nil
+}
+
+func normalizeTagFilter(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
@@ -0,0 +1,94 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type MockTagRepository struct {
+	AddCalled    bool
+	AssignedTask int
+	AssignedTag  int
+	TagToReturn  *Tag
+	TagsByTask   map[int][]Tag
+}
+
+func (m *MockTagRepository) Add(ctx context.Context, tag *Tag) error {
+	m.AddCalled = true
+	return nil
+}
+func (m *MockTagRepository) GetAll(ctx context.Context) ([]Tag, error) { return nil, nil }
+func (m *MockTagRepository) GetById(ctx context.Context, id int) (*Tag, error) {
+	if m.TagToReturn == nil {
+		return nil, ErrTagNotFound
+	}
+	return m.TagToReturn, nil
+}
+func (m *MockTagRepository) Update(ctx context.Context, tag *Tag) error { return nil }
+func (m *MockTagRepository) Delete(ctx context.Context, id int) error   { return nil }
+func (m *MockTagRepository) Assign(ctx context.Context, taskId, tagId int) error {
+	m.AssignedTask = taskId
+	m.AssignedTag = tagId
+	return nil
+}
+func (m *MockTagRepository) Unassign(ctx context.Context, taskId, tagId int) error { return nil }
+func (m *MockTagRepository) GetByTasks(ctx context.Context, taskIds []int) (map[int][]Tag, error) {
+	return m.TagsByTask, nil
+}
+
+func TestCreateTag_Name(t *testing.T) {
+	tests := []struct {
+		name        string
+		tag         string
+		expectedErr error
+	}{
+		{name: "Успешное создание", tag: " bug "},
+		{name: "Предельная длина", tag: strings.Repeat("я", maxTagNameLen)},
+		{name: "Ошибка: пустое имя", tag: "   ", expectedErr: ErrEmptyTagName},
+		{name: "Ошибка: слишком длинное имя", tag: strings.Repeat("я", maxTagNameLen+1), expectedErr: ErrTagNameLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTags := &MockTagRepository{}
+			service := NewService(&MockRepository{}, nil, WithTags(mockTags))
+			_, err := service.CreateTag(context.Background(), tt.tag)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if mockTags.AddCalled != (tt.expectedErr == nil) {
+				t.Errorf("AddCalled = %v, а ожидалось %v", mockTags.AddCalled, tt.expectedErr == nil)
+			}
+		})
+	}
+}
+
+func TestAssignTag_TagNotFound(t *testing.T) {
+	mockTags := &MockTagRepository{}
+	service := NewService(&MockRepository{TaskToReturn: &Task{ID: 1}}, nil, WithTags(mockTags))
+	err := service.AssignTag(context.Background(), 1, 5)
+	if !errors.Is(err, ErrTagNotFound) {
+		t.Errorf("ожидалась ошибка %v, получена %v", ErrTagNotFound, err)
+	}
+	if mockTags.AssignedTag != 0 {
+		t.Error("тег не должен был назначиться")
+	}
+}
+
+func TestGetAllTasks_Tags(t *testing.T) {
+	mockRepo := &MockRepository{TasksToReturn: []Task{{ID: 1}, {ID: 2}}}
+	mockTags := &MockTagRepository{TagsByTask: map[int][]Tag{1: {{ID: 3, Name: "bug"}}}}
+	service := NewService(mockRepo, nil, WithTags(mockTags))
+	tasks, err := service.GetAllTasks(context.Background(), TaskFilter{Tags: []string{" bug", "bug", "", "customer"}, AllTags: true})
+	if err != nil {
+		t.Fatalf("не ожидалось ошибки, получена: %v", err)
+	}
+	if got := mockRepo.GetAllCalledWith.Tags; !slices.Equal(got, []string{"bug", "customer"}) {
+		t.Errorf("в репозиторий ушли теги %v", got)
+	}
+	if len(tasks[0].Tags) != 1 || tasks[0].Tags[0].Name != "bug" || tasks[1].Tags != nil {
+		t.Errorf("теги задач не совпадают: %+v", tasks)
+	}
+}
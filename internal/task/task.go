@@ -2,6 +2,9 @@ package task
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -13,29 +16,120 @@ const (
 	StatusDone       TaskStatus = "done"
 )
 
+type TaskPriority string
+
+const (
+	PriorityLow    TaskPriority = "low"
+	PriorityNormal TaskPriority = "normal"
+	PriorityHigh   TaskPriority = "high"
+	PriorityUrgent TaskPriority = "urgent"
+)
+
+var priorities = []TaskPriority{PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent}
+
 type Task struct {
-	ID          int        `json:"id"`
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	Created     time.Time  `json:"created"`
-	Status      TaskStatus `json:"status"`
-	GroupID     *int       `json:"group_id"`
-	GroupName   *string    `json:"group_name"`
+	ID              int          `json:"id"`
+	Name            string       `json:"name"`
+	Description     string       `json:"description"`
+	Created         time.Time    `json:"created"`
+	Status          TaskStatus   `json:"status"`
+	Priority        TaskPriority `json:"priority"`
+	GroupID         *int         `json:"group_id"`
+	GroupName       *string      `json:"group_name"`
+	StartAt         *time.Time   `json:"start_at"`
+	DueAt           *time.Time   `json:"due_at"`
+	ParentID        *int         `json:"parent_id"`
+	AssigneeID      *int         `json:"assignee_id"`
+	Recurrence      *string      `json:"recurrence"`
+	RecurrenceStart *time.Time   `json:"recurrence_start"`
+	Rank            float64      `json:"rank"`
+	CompletedAt     *time.Time   `json:"completed_at"`
+	Version         int          `json:"version"`
+	DeletedAt       *time.Time   `json:"deleted_at,omitempty"`
+	Progress        *Progress    `json:"progress,omitempty"`
+	Subtasks        []Task       `json:"subtasks,omitempty"`
+	Tags            []Tag        `json:"tags,omitempty"`
+	NextOccurrence  *Task        `json:"next_occurrence,omitempty"`
+}
+
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+type TaskFilter struct {
+	GroupID    *int
+	GroupSlug  string
+	Recursive  bool
+	GroupIDs   []int
+	Status     *TaskStatus
+	ParentID   *int
+	AssigneeID *int
+	DueBefore  *time.Time
+	DueAfter   *time.Time
+	Overdue    bool
+	Tags       []string
+	AllTags    bool
+	Sort       []SortField
+}
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+var sortableFields = []string{"id", "name", "created", "status", "priority", "start_at", "due_at", "rank"}
+
+// ParseSort parses a comma separated list of fields, each optionally prefixed
+// with "-" for descending order, e.g. "-priority,created".
+func ParseSort(value string) ([]SortField, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var fields []SortField
+	for _, part := range strings.Split(value, ",") {
+		f := SortField{Field: strings.TrimSpace(part)}
+		if name, ok := strings.CutPrefix(f.Field, "-"); ok {
+			f.Field = name
+			f.Desc = true
+		}
+		if !slices.Contains(sortableFields, f.Field) {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidSort, f.Field)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
 }
 
 type TaskRepository interface {
 	Add(ctx context.Context, task *Task) error
-	GetAll(ctx context.Context, groupId *int) ([]Task, error)
+	GetAll(ctx context.Context, filter TaskFilter) ([]Task, error)
+	Count(ctx context.Context, filter TaskFilter) (int, error)
 	GetById(ctx context.Context, id int) (*Task, error)
 	Update(ctx context.Context, task *Task) error
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id, version int) error
+	GetTrash(ctx context.Context, deletedBefore time.Time) ([]Task, error)
+	GetTrashed(ctx context.Context, id int) (*Task, error)
+	Restore(ctx context.Context, id int) error
+	Purge(ctx context.Context, id int) error
+	Move(ctx context.Context, id int, afterId, beforeId *int) (float64, error)
+	ReassignGroup(ctx context.Context, fromGroupId int, toGroupId *int) (int, error)
 }
 
+// IsValid only checks the status format; which statuses a task may take is
+// decided by the workflow of its group.
 func (s TaskStatus) IsValid() bool {
-	switch s {
-	case StatusNew, StatusInProgress, StatusDone:
-		return true
-	default:
+	if s == "" || len(s) > 64 {
 		return false
 	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+func (p TaskPriority) IsValid() bool {
+	return slices.Contains(priorities, p)
 }
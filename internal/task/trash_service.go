@@ -0,0 +1,171 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+type Trash struct {
+	Tasks  []Task  `json:"tasks"`
+	Groups []Group `json:"groups"`
+}
+
+func (s *Service) GetTrash(ctx context.Context) (*Trash, error) {
+	now := time.Now()
+	tasks, err := s.repo.GetTrash(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trashed tasks: %w", err)
+	}
+	groups, err := s.groups.GetTrash(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trashed groups: %w", err)
+	}
+	return &Trash{Tasks: tasks, Groups: groups}, nil
+}
+
+// RestoreTask brings a task back from the trash. Its group and parent have to
+// be restored first.
+func (s *Service) RestoreTask(ctx context.Context, id int) (*Task, error) {
+	var task *Task
+	err := s.atomically(ctx, func(tx *Service) error {
+		tasks, groups := tx.repo, tx.groups
+		var err error
+		if task, err = tasks.GetTrashed(ctx, id); err != nil {
+			return fmt.Errorf("failed to get trashed task: %w", err)
+		}
+		if task.GroupID != nil {
+			if _, err := groups.GetById(ctx, *task.GroupID); err != nil {
+				return fmt.Errorf("failed to get task group: %w", err)
+			}
+		}
+		if task.ParentID != nil {
+			parent, err := tasks.GetById(ctx, *task.ParentID)
+			if err != nil {
+				if errors.Is(err, ErrTaskNotFound) {
+					return ErrParentNotFound
+				}
+				return fmt.Errorf("failed to get parent task: %w", err)
+			}
+			if err := tx.checkParentOpen(ctx, parent); err != nil {
+				return err
+			}
+		}
+		if task.Status == StatusInProgress {
+			if err := checkWIPLimit(ctx, tasks, groups, task.GroupID, 1); err != nil {
+				return err
+			}
+		}
+		if err := tasks.Restore(ctx, id); err != nil {
+			return fmt.Errorf("failed to restore task: %w", err)
+		}
+		if task, err = tx.GetTask(ctx, id); err != nil {
+			return err
+		}
+		return tx.record(ctx, ActionRestore, nil, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (s *Service) RestoreGroup(ctx context.Context, id int) (*Group, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("incorrect id: %d", id)
+	}
+	var group *Group
+	err := s.atomically(ctx, func(tx *Service) error {
+		trashed, err := tx.groups.GetTrashed(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get trashed group: %w", err)
+		}
+		if parentId := trashed.ParentID; parentId != nil {
+			if _, err := tx.groups.GetById(ctx, *parentId); err != nil {
+				if errors.Is(err, ErrGroupNotFound) {
+					return ErrParentGroupNotFound
+				}
+				return fmt.Errorf("failed to get parent group: %w", err)
+			}
+		}
+		if err := tx.groups.Restore(ctx, id); err != nil {
+			return fmt.Errorf("failed to restore group: %w", err)
+		}
+		group, err = tx.GetGroup(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// PurgeTrash permanently removes tasks and groups trashed before the given
+// time and returns how many were removed. Items still referenced by newer
+// trash entries are left for a later run.
+func (s *Service) PurgeTrash(ctx context.Context, deletedBefore time.Time) (int, error) {
+	tasks, err := s.repo.GetTrash(ctx, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get trashed tasks: %w", err)
+	}
+	purged := 0
+	for _, t := range tasks {
+		blobs, err := s.taskBlobs(ctx, t.ID)
+		if err != nil {
+			return purged, err
+		}
+		err = s.atomically(ctx, func(tx *Service) error {
+			if err := tx.repo.Purge(ctx, t.ID); err != nil {
+				return err
+			}
+			return tx.record(ctx, ActionPurge, &t, nil)
+		})
+		if errors.Is(err, ErrTaskHasSubtasks) {
+			continue
+		}
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge task: %w", err)
+		}
+		purged++
+		for _, key := range blobs {
+			s.removeBlob(ctx, key)
+		}
+	}
+
+	groups, err := s.groups.GetTrash(ctx, deletedBefore)
+	if err != nil {
+		return purged, fmt.Errorf("failed to get trashed groups: %w", err)
+	}
+	for _, g := range groups {
+		if err := s.groups.Purge(ctx, g.ID); err != nil {
+			if errors.Is(err, ErrGroupHasTasks) {
+				continue
+			}
+			return purged, fmt.Errorf("failed to purge group: %w", err)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// RunTrashPurge purges items older than retention every interval until ctx is
+// done.
+func (s *Service) RunTrashPurge(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		purged, err := s.PurgeTrash(ctx, time.Now().Add(-retention))
+		if err != nil {
+			log.Printf("Ошибка очистки корзины: %v", err)
+		} else if purged > 0 {
+			log.Printf("Из корзины удалено записей: %d", purged)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDeleteTask_HasSubtasks(t *testing.T) {
+	parentID := 1
+	mockRepo := &MockRepository{
+		TaskToReturn:  &Task{ID: 1, Status: StatusNew},
+		TasksToReturn: []Task{{ID: 2, ParentID: &parentID, Status: StatusNew}},
+	}
+	service := NewService(mockRepo, nil)
+	err := service.DeleteTask(context.Background(), 1, nil)
+	if !errors.Is(err, ErrTaskHasSubtasks) {
+		t.Fatalf("ожидалась ошибка %v, получена %v", ErrTaskHasSubtasks, err)
+	}
+}
+
+func TestDeleteGroup_HasTasks(t *testing.T) {
+	groupID := 3
+	mockRepo := &MockRepository{TasksToReturn: []Task{{ID: 1, GroupID: &groupID}}}
+	service := NewService(mockRepo, &MockGroupRepository{})
+	_, err := service.DeleteGroup(context.Background(), 3, DeleteRestrict, nil)
+	if !errors.Is(err, ErrGroupHasTasks) {
+		t.Fatalf("ожидалась ошибка %v, получена %v", ErrGroupHasTasks, err)
+	}
+}
+
+func TestRestoreTask(t *testing.T) {
+	parentID := 1
+	tests := []struct {
+		name        string
+		trashed     Task
+		live        map[int]*Task
+		expectedErr error
+	}{
+		{
+			name:    "Успешное восстановление",
+			trashed: Task{ID: 1, Status: StatusNew},
+		},
+		{
+			name:        "Ошибка: родитель в корзине",
+			trashed:     Task{ID: 2, ParentID: &parentID, Status: StatusNew},
+			live:        map[int]*Task{},
+			expectedErr: ErrParentNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{TrashToReturn: []Task{tt.trashed}, TasksByID: tt.live}
+			service := NewService(mockRepo, &MockGroupRepository{})
+			_, err := service.RestoreTask(context.Background(), tt.trashed.ID)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if mockRepo.RestoreCalled != (tt.expectedErr == nil) {
+				t.Errorf("RestoreCalled = %v, а ожидалось %v", mockRepo.RestoreCalled, tt.expectedErr == nil)
+			}
+		})
+	}
+}
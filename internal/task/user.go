@@ -0,0 +1,17 @@
+package task
+
+import "context"
+
+type User struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type UserRepository interface {
+	Add(ctx context.Context, user *User) error
+	GetAll(ctx context.Context) ([]User, error)
+	GetById(ctx context.Context, id int) (*User, error)
+	Update(ctx context.Context, user *User) error
+	Delete(ctx context.Context, id int) error
+}
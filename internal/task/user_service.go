@@ -0,0 +1,109 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+var (
+	ErrUsersDisabled = errors.New("users are not configured")
+	ErrEmptyUserName = errors.New("user name cannot be empty")
+	ErrInvalidEmail  = errors.New("invalid user email")
+	ErrNotUniqUser   = errors.New("user has not unique email")
+	ErrUserNotFound  = errors.New("user not found")
+)
+
+func (s *Service) CreateUser(ctx context.Context, name, email string) (*User, error) {
+	user, err := s.validateUser(name, email)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.users.Add(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to add user: %w", err)
+	}
+	return user, nil
+}
+
+func (s *Service) GetUser(ctx context.Context, id int) (*User, error) {
+	if s.users == nil {
+		return nil, ErrUsersDisabled
+	}
+	if id <= 0 {
+		return nil, fmt.Errorf("incorrect id: %d", id)
+	}
+	user, err := s.users.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+func (s *Service) ListUsers(ctx context.Context) ([]User, error) {
+	if s.users == nil {
+		return nil, ErrUsersDisabled
+	}
+	users, err := s.users.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all users: %w", err)
+	}
+	return users, nil
+}
+
+func (s *Service) UpdateUser(ctx context.Context, id int, name, email string) (*User, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("incorrect id: %d", id)
+	}
+	user, err := s.validateUser(name, email)
+	if err != nil {
+		return nil, err
+	}
+	user.ID = id
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	return user, nil
+}
+
+func (s *Service) DeleteUser(ctx context.Context, id int) error {
+	if s.users == nil {
+		return ErrUsersDisabled
+	}
+	if id <= 0 {
+		return fmt.Errorf("incorrect id: %d", id)
+	}
+	if err := s.users.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) validateUser(name, email string) (*User, error) {
+	if s.users == nil {
+		return nil, ErrUsersDisabled
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrEmptyUserName
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return nil, ErrInvalidEmail
+	}
+	return &User{Name: name, Email: email}, nil
+}
+
+func (s *Service) checkUser(ctx context.Context, userId *int) error {
+	if userId == nil {
+		return nil
+	}
+	if s.users == nil {
+		return ErrUsersDisabled
+	}
+	if _, err := s.users.GetById(ctx, *userId); err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	return nil
+}
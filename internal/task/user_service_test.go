@@ -0,0 +1,83 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type MockUserRepository struct {
+	AddCalled    bool
+	UserToReturn *User
+}
+
+func (m *MockUserRepository) Add(ctx context.Context, user *User) error {
+	m.AddCalled = true
+	return nil
+}
+func (m *MockUserRepository) GetAll(ctx context.Context) ([]User, error) { return nil, nil }
+func (m *MockUserRepository) GetById(ctx context.Context, id int) (*User, error) {
+	if m.UserToReturn == nil || m.UserToReturn.ID != id {
+		return nil, ErrUserNotFound
+	}
+	return m.UserToReturn, nil
+}
+func (m *MockUserRepository) Update(ctx context.Context, user *User) error { return nil }
+func (m *MockUserRepository) Delete(ctx context.Context, id int) error     { return nil }
+
+func TestCreateUser_Validation(t *testing.T) {
+	tests := []struct {
+		name        string
+		userName    string
+		email       string
+		expectedErr error
+	}{
+		{name: "Успешное создание", userName: "Анна", email: " Anna@Example.com "},
+		{name: "Ошибка: пустое имя", userName: " ", email: "anna@example.com", expectedErr: ErrEmptyUserName},
+		{name: "Ошибка: неверный email", userName: "Анна", email: "Анна <anna@example.com>", expectedErr: ErrInvalidEmail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsers := &MockUserRepository{}
+			service := NewService(&MockRepository{}, nil, WithUsers(mockUsers))
+			user, err := service.CreateUser(context.Background(), tt.userName, tt.email)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if mockUsers.AddCalled != (tt.expectedErr == nil) {
+				t.Errorf("AddCalled = %v, а ожидалось %v", mockUsers.AddCalled, tt.expectedErr == nil)
+			}
+			if err == nil && user.Email != "anna@example.com" {
+				t.Errorf("ожидался нормализованный email, получен %q", user.Email)
+			}
+		})
+	}
+}
+
+func TestCreateTask_Assignee(t *testing.T) {
+	tests := []struct {
+		name        string
+		assigneeID  int
+		expectedErr error
+	}{
+		{name: "Существующий исполнитель", assigneeID: 1},
+		{name: "Ошибка: исполнитель не найден", assigneeID: 2, expectedErr: ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{}
+			mockUsers := &MockUserRepository{UserToReturn: &User{ID: 1, Name: "Анна"}}
+			service := NewService(mockRepo, nil, WithUsers(mockUsers))
+			task, err := service.CreateTask(context.Background(), CreateTaskInput{Name: "Задача", AssigneeID: &tt.assigneeID})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if mockRepo.AddCalled != (tt.expectedErr == nil) {
+				t.Errorf("AddCalled = %v, а ожидалось %v", mockRepo.AddCalled, tt.expectedErr == nil)
+			}
+			if err == nil && (task.AssigneeID == nil || *task.AssigneeID != tt.assigneeID) {
+				t.Errorf("исполнитель не сохранён: %v", task.AssigneeID)
+			}
+		})
+	}
+}
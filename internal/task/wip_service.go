@@ -0,0 +1,50 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrWIPLimitExceeded = errors.New("group work in progress limit exceeded")
+	ErrInvalidWIPLimit  = errors.New("work in progress limit must be positive")
+)
+
+// checkWIPLimit fails when adding more in_progress tasks to the group would
+// exceed its work in progress limit.
+func checkWIPLimit(ctx context.Context, tasks TaskRepository, groups GroupRepository, groupId *int, adding int) error {
+	if groupId == nil || adding <= 0 {
+		return nil
+	}
+	group, err := groups.GetById(ctx, *groupId)
+	if err != nil {
+		return fmt.Errorf("failed to get group: %w", err)
+	}
+	if group.WIPLimit == nil {
+		return nil
+	}
+	load, err := wipLoad(ctx, tasks, *groupId)
+	if err != nil {
+		return err
+	}
+	if load+adding > *group.WIPLimit {
+		return fmt.Errorf("%w: %d of %d tasks in progress", ErrWIPLimitExceeded, load, *group.WIPLimit)
+	}
+	return nil
+}
+
+func wipLoad(ctx context.Context, tasks TaskRepository, groupId int) (int, error) {
+	status := StatusInProgress
+	load, err := tasks.Count(ctx, TaskFilter{GroupID: &groupId, Status: &status})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tasks in progress: %w", err)
+	}
+	return load, nil
+}
+
+// enteringWIP reports whether a task ends up in progress in a group it was
+// not in progress in before.
+func enteringWIP(fromGroup *int, from TaskStatus, toGroup *int, to TaskStatus) bool {
+	return to == StatusInProgress && (from != StatusInProgress || !sameID(fromGroup, toGroup))
+}
@@ -0,0 +1,55 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUpdateTask_WIPLimit(t *testing.T) {
+	groupID, limit := 1, 2
+	tests := []struct {
+		name        string
+		status      TaskStatus
+		to          TaskStatus
+		load        int
+		expectedErr error
+	}{
+		{name: "Лимит не превышен", status: StatusNew, to: StatusInProgress, load: 1},
+		{name: "Ошибка: лимит превышен", status: StatusNew, to: StatusInProgress, load: 2, expectedErr: ErrWIPLimitExceeded},
+		{name: "Задача уже в работе", status: StatusInProgress, to: StatusInProgress, load: 2},
+		{name: "Возврат в новые", status: StatusInProgress, to: StatusNew, load: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{
+				TaskToReturn:  &Task{ID: 1, Name: "Задача", Status: tt.status, GroupID: &groupID, Created: time.Now()},
+				CountToReturn: tt.load,
+			}
+			mockGroupRepo := &MockGroupRepository{GroupToReturn: &Group{ID: groupID, WIPLimit: &limit}}
+			service := NewService(mockRepo, mockGroupRepo)
+			in := UpdateTaskInput{CreateTaskInput: CreateTaskInput{Name: "Задача", GroupID: &groupID}, Status: tt.to}
+			_, err := service.UpdateTask(context.Background(), 1, in)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if mockRepo.UpdateCalled != (tt.expectedErr == nil) {
+				t.Errorf("UpdateCalled = %v, а ожидалось %v", mockRepo.UpdateCalled, tt.expectedErr == nil)
+			}
+		})
+	}
+}
+
+func TestGetGroup_WIPLoad(t *testing.T) {
+	limit := 3
+	mockGroupRepo := &MockGroupRepository{GroupToReturn: &Group{ID: 1, Name: "Работа", WIPLimit: &limit}}
+	service := NewService(&MockRepository{CountToReturn: 2}, mockGroupRepo)
+	g, err := service.GetGroup(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if g.WIPLoad == nil || *g.WIPLoad != 2 {
+		t.Errorf("ожидалась загрузка 2, получено %v", g.WIPLoad)
+	}
+}
@@ -0,0 +1,141 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidStatus        = errors.New("status is not defined by workflow")
+	ErrTransitionNotAllowed = errors.New("status transition is not allowed by workflow")
+	ErrGuardFailed          = errors.New("status transition guard failed")
+	ErrInvalidWorkflow      = errors.New("invalid workflow")
+)
+
+type Transition struct {
+	From   TaskStatus `json:"from"`
+	To     TaskStatus `json:"to"`
+	Guards []string   `json:"guards,omitempty"`
+}
+
+type Workflow struct {
+	Initial     TaskStatus   `json:"initial"`
+	States      []TaskStatus `json:"states"`
+	Terminal    []TaskStatus `json:"terminal"`
+	Transitions []Transition `json:"transitions"`
+}
+
+// Guard checks a task as it would look after the transition.
+type Guard func(t *Task) error
+
+var workflowGuards = map[string]Guard{
+	"has_description": func(t *Task) error {
+		if strings.TrimSpace(t.Description) == "" {
+			return errors.New("task description is required")
+		}
+		return nil
+	},
+	"has_due_date": func(t *Task) error {
+		if t.DueAt == nil {
+			return errors.New("task due date is required")
+		}
+		return nil
+	},
+	"has_start_date": func(t *Task) error {
+		if t.StartAt == nil {
+			return errors.New("task start date is required")
+		}
+		return nil
+	},
+}
+
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Initial:  StatusNew,
+		States:   []TaskStatus{StatusNew, StatusInProgress, StatusDone},
+		Terminal: []TaskStatus{StatusDone},
+		Transitions: []Transition{
+			{From: StatusNew, To: StatusInProgress},
+			{From: StatusInProgress, To: StatusNew},
+			{From: StatusInProgress, To: StatusDone},
+		},
+	}
+}
+
+func (w *Workflow) HasState(s TaskStatus) bool {
+	return slices.Contains(w.States, s)
+}
+
+func (w *Workflow) IsTerminal(s TaskStatus) bool {
+	return slices.Contains(w.Terminal, s)
+}
+
+func (w *Workflow) Validate() error {
+	if len(w.States) == 0 {
+		return fmt.Errorf("%w: no states", ErrInvalidWorkflow)
+	}
+	seen := make(map[TaskStatus]bool, len(w.States))
+	for _, s := range w.States {
+		if !s.IsValid() {
+			return fmt.Errorf("%w: invalid state %q", ErrInvalidWorkflow, s)
+		}
+		if seen[s] {
+			return fmt.Errorf("%w: duplicate state %q", ErrInvalidWorkflow, s)
+		}
+		seen[s] = true
+	}
+	if !seen[w.Initial] {
+		return fmt.Errorf("%w: unknown initial state %q", ErrInvalidWorkflow, w.Initial)
+	}
+	if w.IsTerminal(w.Initial) {
+		return fmt.Errorf("%w: initial state %q cannot be terminal", ErrInvalidWorkflow, w.Initial)
+	}
+	for _, s := range w.Terminal {
+		if !seen[s] {
+			return fmt.Errorf("%w: unknown terminal state %q", ErrInvalidWorkflow, s)
+		}
+	}
+	for _, tr := range w.Transitions {
+		if !seen[tr.From] || !seen[tr.To] {
+			return fmt.Errorf("%w: transition %s -> %s uses unknown state", ErrInvalidWorkflow, tr.From, tr.To)
+		}
+		if tr.From == tr.To {
+			return fmt.Errorf("%w: transition %s -> %s is a loop", ErrInvalidWorkflow, tr.From, tr.To)
+		}
+		if w.IsTerminal(tr.From) {
+			return fmt.Errorf("%w: transition out of terminal state %q", ErrInvalidWorkflow, tr.From)
+		}
+		for _, name := range tr.Guards {
+			if _, ok := workflowGuards[name]; !ok {
+				return fmt.Errorf("%w: unknown guard %q", ErrInvalidWorkflow, name)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckTransition validates moving t from its stored status to status `to`.
+// A status the workflow does not know (e.g. after moving the task to another
+// group) can be left for any state of the workflow.
+func (w *Workflow) CheckTransition(t *Task, from, to TaskStatus) error {
+	if !w.HasState(to) {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, to)
+	}
+	if from == to || !w.HasState(from) {
+		return nil
+	}
+	idx := slices.IndexFunc(w.Transitions, func(tr Transition) bool {
+		return tr.From == from && tr.To == to
+	})
+	if idx < 0 {
+		return fmt.Errorf("%w: %s -> %s", ErrTransitionNotAllowed, from, to)
+	}
+	for _, name := range w.Transitions[idx].Guards {
+		if err := workflowGuards[name](t); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrGuardFailed, name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func reviewWorkflow() *Workflow {
+	return &Workflow{
+		Initial:  StatusNew,
+		States:   []TaskStatus{StatusNew, StatusInProgress, "review", StatusDone, "cancelled"},
+		Terminal: []TaskStatus{StatusDone, "cancelled"},
+		Transitions: []Transition{
+			{From: StatusNew, To: StatusInProgress},
+			{From: StatusNew, To: "cancelled"},
+			{From: StatusInProgress, To: "review", Guards: []string{"has_description"}},
+			{From: "review", To: StatusInProgress},
+			{From: "review", To: StatusDone},
+		},
+	}
+}
+
+func TestWorkflow_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(w *Workflow)
+		wantErr bool
+	}{
+		{name: "Корректный", mutate: func(w *Workflow) {}},
+		{name: "Неизвестный начальный статус", mutate: func(w *Workflow) { w.Initial = "todo" }, wantErr: true},
+		{name: "Начальный статус конечный", mutate: func(w *Workflow) { w.Terminal = append(w.Terminal, StatusNew) }, wantErr: true},
+		{name: "Переход из конечного", mutate: func(w *Workflow) {
+			w.Transitions = append(w.Transitions, Transition{From: StatusDone, To: StatusNew})
+		}, wantErr: true},
+		{name: "Неизвестный guard", mutate: func(w *Workflow) { w.Transitions[0].Guards = []string{"nope"} }, wantErr: true},
+		{name: "Некорректное имя статуса", mutate: func(w *Workflow) { w.States = append(w.States, "In Review") }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := reviewWorkflow()
+			tt.mutate(w)
+			err := w.Validate()
+			if tt.wantErr != errors.Is(err, ErrInvalidWorkflow) {
+				t.Errorf("ожидалась ошибка: %v, получена %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUpdateTask_GroupWorkflow(t *testing.T) {
+	groupID := 1
+	tests := []struct {
+		name           string
+		existingStatus TaskStatus
+		description    string
+		newStatus      TaskStatus
+		expectedErr    error
+	}{
+		{name: "Переход в review", existingStatus: StatusInProgress, description: "Готово", newStatus: "review"},
+		{name: "Ошибка: guard без описания", existingStatus: StatusInProgress, newStatus: "review", expectedErr: ErrGuardFailed},
+		{name: "Ошибка: переход не описан", existingStatus: StatusInProgress, newStatus: StatusDone, expectedErr: ErrTransitionNotAllowed},
+		{name: "Отмена новой задачи", existingStatus: StatusNew, newStatus: "cancelled"},
+		{name: "Ошибка: отменённая задача", existingStatus: "cancelled", newStatus: StatusNew, expectedErr: ErrDoneEdit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockRepository{
+				TaskToReturn: &Task{Status: tt.existingStatus, GroupID: &groupID},
+			}
+			mockGroupRepo := &MockGroupRepository{WorkflowToReturn: reviewWorkflow()}
+			service := NewService(mockRepo, mockGroupRepo)
+			_, err := service.UpdateTask(context.Background(), 1, UpdateTaskInput{
+				CreateTaskInput: CreateTaskInput{Name: "Задача", Description: tt.description, GroupID: &groupID},
+				Status:          tt.newStatus,
+			})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ожидалась ошибка %v, получена %v", tt.expectedErr, err)
+			}
+			if mockRepo.UpdateCalled != (tt.expectedErr == nil) {
+				t.Errorf("UpdateCalled = %v, а ожидалось %v", mockRepo.UpdateCalled, tt.expectedErr == nil)
+			}
+		})
+	}
+}
+
+func TestSetWorkflow_StateInUse(t *testing.T) {
+	mockRepo := &MockRepository{
+		TasksToReturn: []Task{{ID: 7, Status: "review"}},
+	}
+	mockGroupRepo := &MockGroupRepository{}
+	service := NewService(mockRepo, mockGroupRepo)
+	_, err := service.SetWorkflow(context.Background(), 1, nil)
+	if !errors.Is(err, ErrInvalidWorkflow) {
+		t.Fatalf("ожидалась ошибка %v, получена %v", ErrInvalidWorkflow, err)
+	}
+
+	mockRepo.TasksToReturn = []Task{{ID: 7, Status: StatusInProgress}}
+	w := reviewWorkflow()
+	if _, err := service.SetWorkflow(context.Background(), 1, w); err != nil {
+		t.Fatalf("не ожидалось ошибки, получена: %v", err)
+	}
+	if mockGroupRepo.SavedWorkflow != w {
+		t.Error("workflow не был сохранён")
+	}
+}
@@ -0,0 +1,4 @@
+ALTER TABLE tasks ADD COLUMN start_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN due_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_due_at ON tasks (due_at);
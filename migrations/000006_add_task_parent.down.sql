@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_parent_id;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS fk_task_parent;
+ALTER TABLE tasks DROP COLUMN parent_id;
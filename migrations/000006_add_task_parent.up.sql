@@ -0,0 +1,4 @@
+ALTER TABLE tasks ADD COLUMN parent_id INT;
+ALTER TABLE tasks ADD CONSTRAINT fk_task_parent FOREIGN KEY (parent_id) REFERENCES tasks(id);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks (parent_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_tasks_assignee_id;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS fk_task_assignee;
+ALTER TABLE tasks DROP COLUMN assignee_id;
+DROP TABLE users;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS attachments (
+    id SERIAL PRIMARY KEY,
+    task_id INT NOT NULL,
+    filename VARCHAR(256) NOT NULL,
+    content_type VARCHAR(128) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(64) NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_attachment_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    CONSTRAINT idx_attachments_storage_key_unique UNIQUE (storage_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_task_id ON attachments (task_id);
@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN recurrence_start;
+ALTER TABLE tasks DROP COLUMN recurrence;
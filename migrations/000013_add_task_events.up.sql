@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS task_events (
+    id BIGSERIAL PRIMARY KEY,
+    task_id INT NOT NULL,
+    actor_id INT,
+    action VARCHAR(32) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_events_task_id ON task_events (task_id, created);
+CREATE INDEX IF NOT EXISTS idx_task_events_created ON task_events (created);
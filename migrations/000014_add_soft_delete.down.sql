@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_groups_name_unique;
+ALTER TABLE groups ADD CONSTRAINT idx_groups_name_unique UNIQUE (name);
+
+DROP INDEX IF EXISTS idx_groups_deleted_at;
+DROP INDEX IF EXISTS idx_tasks_deleted_at;
+
+ALTER TABLE groups DROP COLUMN deleted_at;
+ALTER TABLE tasks DROP COLUMN deleted_at;
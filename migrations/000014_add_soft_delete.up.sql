@@ -0,0 +1,8 @@
+ALTER TABLE tasks ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE groups ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_groups_deleted_at ON groups (deleted_at) WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE groups DROP CONSTRAINT IF EXISTS idx_groups_name_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_name_unique ON groups (name) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_created;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_task_priority;
+ALTER TABLE tasks DROP COLUMN priority;
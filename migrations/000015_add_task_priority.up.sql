@@ -0,0 +1,4 @@
+ALTER TABLE tasks ADD COLUMN priority VARCHAR(16) NOT NULL DEFAULT 'normal';
+ALTER TABLE tasks ADD CONSTRAINT chk_task_priority CHECK (priority IN ('low', 'normal', 'high', 'urgent'));
+
+CREATE INDEX IF NOT EXISTS idx_tasks_created ON tasks (created, id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_column_rank;
+ALTER TABLE tasks DROP COLUMN rank;
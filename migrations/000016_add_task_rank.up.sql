@@ -0,0 +1,11 @@
+ALTER TABLE tasks ADD COLUMN rank DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+UPDATE tasks t
+SET rank = r.pos * 1024
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY group_id, status ORDER BY created, id) AS pos
+    FROM tasks
+) r
+WHERE t.id = r.id;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_column_rank ON tasks (group_id, status, rank);
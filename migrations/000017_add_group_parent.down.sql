@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_groups_name_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_name_unique ON groups (name) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_groups_parent_id;
+ALTER TABLE groups DROP CONSTRAINT IF EXISTS fk_group_parent;
+ALTER TABLE groups DROP COLUMN parent_id;
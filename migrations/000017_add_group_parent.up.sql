@@ -0,0 +1,7 @@
+ALTER TABLE groups ADD COLUMN parent_id INT;
+ALTER TABLE groups ADD CONSTRAINT fk_group_parent FOREIGN KEY (parent_id) REFERENCES groups(id);
+
+CREATE INDEX IF NOT EXISTS idx_groups_parent_id ON groups (parent_id);
+
+DROP INDEX IF EXISTS idx_groups_name_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_name_unique ON groups (COALESCE(parent_id, 0), name) WHERE deleted_at IS NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE groups DROP CONSTRAINT IF EXISTS fk_group_owner;
+
+ALTER TABLE groups DROP COLUMN archived;
+ALTER TABLE groups DROP COLUMN owner_id;
+ALTER TABLE groups DROP COLUMN icon;
+ALTER TABLE groups DROP COLUMN color;
+ALTER TABLE groups DROP COLUMN description;
@@ -0,0 +1,7 @@
+ALTER TABLE groups ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE groups ADD COLUMN color VARCHAR(7) NOT NULL DEFAULT '';
+ALTER TABLE groups ADD COLUMN icon VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE groups ADD COLUMN owner_id INT;
+ALTER TABLE groups ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE groups ADD CONSTRAINT fk_group_owner FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE groups DROP CONSTRAINT IF EXISTS chk_group_wip_limit;
+ALTER TABLE groups DROP COLUMN wip_limit;
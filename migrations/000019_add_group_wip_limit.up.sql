@@ -0,0 +1,2 @@
+ALTER TABLE groups ADD COLUMN wip_limit INT;
+ALTER TABLE groups ADD CONSTRAINT chk_group_wip_limit CHECK (wip_limit > 0);
@@ -0,0 +1,15 @@
+ALTER TABLE tasks ADD COLUMN completed_at TIMESTAMP;
+
+UPDATE tasks t
+SET completed_at = COALESCE(
+    (SELECT MAX(e.created) FROM task_events e WHERE e.task_id = t.id AND e.changes ? 'status'),
+    t.created
+)
+WHERE t.status IN (
+    SELECT jsonb_array_elements_text(COALESCE(
+        (SELECT g.workflow -> 'terminal' FROM groups g WHERE g.id = t.group_id),
+        '["done"]'
+    ))
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_completed_at ON tasks (completed_at);
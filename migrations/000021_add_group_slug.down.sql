@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_groups_slug_unique;
+DROP INDEX IF EXISTS idx_groups_name_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_name_unique ON groups (COALESCE(parent_id, 0), name) WHERE deleted_at IS NULL;
+
+ALTER TABLE groups DROP COLUMN slug;
+ALTER TABLE groups DROP COLUMN name_key;
@@ -0,0 +1,29 @@
+ALTER TABLE groups ADD COLUMN name_key TEXT;
+ALTER TABLE groups ADD COLUMN slug TEXT;
+
+UPDATE groups SET name = regexp_replace(btrim(normalize(name, NFC)), '\s+', ' ', 'g');
+UPDATE groups SET name_key = lower(normalize(name, NFKC));
+
+-- Names that only differed in case or spacing get the id appended.
+UPDATE groups g
+SET name = g.name || ' (' || g.id || ')', name_key = g.name_key || ' (' || g.id || ')'
+WHERE g.deleted_at IS NULL AND EXISTS (
+    SELECT 1 FROM groups o
+    WHERE o.deleted_at IS NULL
+      AND COALESCE(o.parent_id, 0) = COALESCE(g.parent_id, 0)
+      AND o.name_key = g.name_key
+      AND o.id < g.id
+);
+
+UPDATE groups SET slug = btrim(regexp_replace(name_key, '[^[:alnum:]]+', '-', 'g'), '-');
+UPDATE groups SET slug = 'group' WHERE slug = '';
+UPDATE groups g
+SET slug = g.slug || '-' || g.id
+WHERE EXISTS (SELECT 1 FROM groups o WHERE o.slug = g.slug AND o.id < g.id);
+
+ALTER TABLE groups ALTER COLUMN name_key SET NOT NULL;
+ALTER TABLE groups ALTER COLUMN slug SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_groups_name_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_name_unique ON groups (COALESCE(parent_id, 0), name_key) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_slug_unique ON groups (slug) WHERE deleted_at IS NULL;
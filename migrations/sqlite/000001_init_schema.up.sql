@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    name_key TEXT NOT NULL,
+    slug TEXT NOT NULL,
+    parent_id INTEGER,
+    description TEXT NOT NULL DEFAULT '',
+    color TEXT NOT NULL DEFAULT '',
+    icon TEXT NOT NULL DEFAULT '',
+    owner_id INTEGER,
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    wip_limit INTEGER,
+    workflow TEXT,
+    deleted_at TIMESTAMP,
+    CONSTRAINT fk_group_parent FOREIGN KEY (parent_id) REFERENCES groups(id),
+    CONSTRAINT chk_group_wip_limit CHECK (wip_limit > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_groups_parent_id ON groups (parent_id);
+CREATE INDEX IF NOT EXISTS idx_groups_deleted_at ON groups (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_name_unique ON groups (COALESCE(parent_id, 0), name_key) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_slug_unique ON groups (slug) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created TIMESTAMP NOT NULL,
+    status TEXT NOT NULL DEFAULT 'new',
+    priority TEXT NOT NULL DEFAULT 'normal',
+    group_id INTEGER,
+    start_at TIMESTAMP,
+    due_at TIMESTAMP,
+    parent_id INTEGER,
+    assignee_id INTEGER,
+    recurrence TEXT,
+    recurrence_start TIMESTAMP,
+    rank REAL NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT fk_group FOREIGN KEY (group_id) REFERENCES groups(id),
+    CONSTRAINT fk_task_parent FOREIGN KEY (parent_id) REFERENCES tasks(id),
+    CONSTRAINT chk_task_priority CHECK (priority IN ('low', 'normal', 'high', 'urgent'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_group_id ON tasks (group_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks (parent_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_due_at ON tasks (due_at);
+CREATE INDEX IF NOT EXISTS idx_tasks_created ON tasks (created, id);
+CREATE INDEX IF NOT EXISTS idx_tasks_completed_at ON tasks (completed_at);
+CREATE INDEX IF NOT EXISTS idx_tasks_column_rank ON tasks (group_id, status, rank);
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
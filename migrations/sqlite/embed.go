@@ -0,0 +1,9 @@
+// Package sqlite embeds the schema migrations of the SQLite storage; they
+// mirror the Postgres ones in the parent directory for the tables the SQLite
+// backend supports.
+package sqlite
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS